@@ -5,6 +5,7 @@ import (
 	"log"
 
 	appsvc "open-camera-mouse/internal/app"
+	"open-camera-mouse/internal/camera"
 	"open-camera-mouse/internal/config"
 	"open-camera-mouse/internal/hotkeys"
 	"open-camera-mouse/internal/preview"
@@ -18,13 +19,34 @@ type App struct {
 	hk  *hotkeys.Hotkeys
 }
 
-func NewApp() (*App, error) {
+// sourceFlags selects the frame source from the command line; the webcam is
+// used when none is set.
+type sourceFlags struct {
+	videoPath       string
+	synthetic       bool
+	syntheticScript string
+}
+
+// NewApp builds the Wails-facing app. When videoPath is set, frames are played
+// back from that file (looping) instead of being captured from the webcam;
+// with synthetic set, a looping synthetic test pattern is shown instead.
+func NewApp(flags sourceFlags) (*App, error) {
 	cfg, err := config.NewManager("open-camera-mouse")
 	if err != nil {
 		return nil, err
 	}
 
-	inner, err := appsvc.NewApp(cfg)
+	var source camera.FrameSource = camera.NewService(camera.Options{})
+	switch {
+	case flags.videoPath != "":
+		source = camera.NewFileSource(flags.videoPath, true)
+	case flags.synthetic:
+		if source, err = loadSynthetic(flags.syntheticScript, true); err != nil {
+			return nil, err
+		}
+	}
+
+	inner, err := appsvc.NewApp(cfg, source)
 	if err != nil {
 		return nil, err
 	}
@@ -44,6 +66,16 @@ func (a *App) startup(ctx context.Context) {
 	a.app.EmitRunning = func(running bool) {
 		runtime.EventsEmit(ctx, "service:running", running)
 	}
+	a.app.EmitError = func(e appsvc.Error) {
+		runtime.EventsEmit(ctx, "app:error", e)
+	}
+	a.app.EmitCameraState = func(connected bool) {
+		if connected {
+			runtime.EventsEmit(ctx, "camera:reconnected")
+			return
+		}
+		runtime.EventsEmit(ctx, "camera:disconnected")
+	}
 
 	hk, err := hotkeys.Start(
 		a.toggleStartStop,
@@ -59,6 +91,12 @@ func (a *App) startup(ctx context.Context) {
 		go func() {
 			if err := a.Start(); err != nil {
 				a.logErrorf("autostart failed: %v", err)
+				return
+			}
+			if params.AutoPickFace {
+				if err := a.app.SendAutoPick(); err != nil {
+					a.logErrorf("auto pick failed: %v", err)
+				}
 			}
 		}()
 	}
@@ -80,10 +118,25 @@ func (a *App) Stop() error {
 	return nil
 }
 
+// StartRecording begins recording the session for a bug report and returns
+// the directory it is written to.
+func (a *App) StartRecording() (string, error) {
+	return a.app.SendStartRecording()
+}
+
+func (a *App) StopRecording() error {
+	return a.app.SendStopRecording()
+}
+
 func (a *App) PickPoint(x, y int) error {
 	return a.app.SendPickPoint(x, y)
 }
 
+// AutoPick picks the user's face as the tracking target.
+func (a *App) AutoPick() error {
+	return a.app.SendAutoPick()
+}
+
 func (a *App) BeginRecenter() error {
 	return a.app.SendBeginRecenter()
 }
@@ -92,6 +145,24 @@ func (a *App) ConfirmRecenter() error {
 	return a.app.SendConfirmRecenter()
 }
 
+// BeginPoseCalibration starts measuring how far the user turns their head,
+// for absolute pointing.
+func (a *App) BeginPoseCalibration() error {
+	return a.app.SendBeginPoseCalibration()
+}
+
+// FinishPoseCalibration saves the measured head range and returns the
+// updated params.
+func (a *App) FinishPoseCalibration() (config.Params, error) {
+	return a.app.SendFinishPoseCalibration()
+}
+
+// HeadPoseAvailable reports whether pointing with the head can load a face
+// model, the one at modelPath or, if it is empty, the built-in one.
+func (a *App) HeadPoseAvailable(modelPath string) bool {
+	return a.app.HeadPoseAvailable(modelPath)
+}
+
 func (a *App) ResetMouse() error {
 	return a.app.SendResetMouse()
 }
@@ -108,6 +179,21 @@ func (a *App) UpdateParams(params config.Params) error {
 	return a.app.UpdateParams(params)
 }
 
+// LastError returns the most recent runtime error of the current run (or of
+// the failed start before it), or nil, so the UI can show one that was
+// reported before it subscribed to "app:error".
+func (a *App) LastError() *appsvc.Error {
+	return a.app.LastError()
+}
+
+func (a *App) ListCameras() []camera.Device {
+	return a.app.ListCameras()
+}
+
+func (a *App) SelectCamera(deviceID int, devicePath string) error {
+	return a.app.SelectCamera(deviceID, devicePath)
+}
+
 func (a *App) toggleStartStop() {
 	if a.app.IsRunning() {
 		if err := a.Stop(); err != nil {
@@ -0,0 +1,13 @@
+#!/usr/bin/env bash
+# Downloads the model files embedded by internal/tracking into
+# internal/tracking/models, skipping ones already there.
+set -euo pipefail
+DIR=internal/tracking/models
+YUNET=face_detection_yunet_2023mar.onnx
+YUNET_URL=https://github.com/opencv/opencv_zoo/raw/main/models/face_detection_yunet/$YUNET
+
+if [ ! -s "$DIR/$YUNET" ]; then
+  echo "Fetching $YUNET"
+  curl -fsSL --retry 3 -o "$DIR/$YUNET.part" "$YUNET_URL"
+  mv "$DIR/$YUNET.part" "$DIR/$YUNET"
+fi
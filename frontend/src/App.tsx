@@ -1,6 +1,6 @@
 import { useEffect, useState } from "react";
 import { EventsOn } from "../wailsjs/runtime/runtime";
-import { GetParams, Start, Stop } from "../wailsjs/go/main/App";
+import { GetParams, LastError, Start, Stop } from "../wailsjs/go/main/App";
 import { MainScreen } from "./screens/main/MainScreen";
 import { SettingsScreen } from "./screens/settings/SettingsScreen";
 import { useParams } from "./state/useParams";
@@ -9,6 +9,7 @@ import { useRunning } from "./state/useRunning";
 import { useAppError } from "./state/useAppError";
 import { publishPreview } from "./lib/previewBus";
 import { fromBackendParams } from "./lib/params";
+import { describeAppError, ERROR_CODES } from "./lib/appErrors";
 import { useStatus } from "./state/useStatus";
 import type { Params } from "./types/params";
 import { ErrorBanner } from "./components/ErrorBanner";
@@ -21,12 +22,19 @@ function App() {
   const { confirmParams } = useParamsSync();
   const { setRunning } = useRunning();
   const { setStatus } = useStatus();
-  const { error, reportError, clearError } = useAppError();
+  const { error, reportError, clearError, clearErrorCode } = useAppError();
 
   useEffect(() => {
     let offPreview: (() => void) | undefined;
     let offStatus: (() => void) | undefined;
     let offRunning: (() => void) | undefined;
+    let offError: (() => void) | undefined;
+    let offReconnected: (() => void) | undefined;
+
+    const showBackendError = (payload: { code?: string; message?: string }) => {
+      const { message, code, detail } = describeAppError(payload);
+      reportError(message, code, detail);
+    };
 
     GetParams()
       .then((res) => setParams(fromBackendParams(res)))
@@ -35,6 +43,22 @@ function App() {
         reportError("Could not load settings.");
       });
 
+    // Catch up on an error reported before this listener existed (e.g. a
+    // failed autostart).
+    LastError()
+      .then((err) => {
+        if (err) showBackendError(err);
+      })
+      .catch((err) => console.error("failed to load last error", err));
+
+    offError = EventsOn("app:error", (payload) => {
+      if (payload) showBackendError(payload);
+    });
+
+    offReconnected = EventsOn("camera:reconnected", () => {
+      clearErrorCode(ERROR_CODES.cameraRead);
+    });
+
     offPreview = EventsOn("preview:frame", (frame) => {
       if (!frame?.dataUrl) return;
       publishPreview({
@@ -46,7 +70,16 @@ function App() {
     });
 
     offStatus = EventsOn("status:update", (payload) => {
-      setStatus({ lost: payload?.lost ?? false });
+      setStatus({
+        lost: payload?.lost ?? false,
+        reacquiring: payload?.reacquiring ?? false,
+        camera: payload?.camera ?? null,
+        cameraDisconnected: payload?.cameraDisconnected ?? false,
+        recording: payload?.recording ?? false,
+        score: payload?.score ?? 0,
+        sharpness: payload?.sharpness ?? 0,
+        secondBestRatio: payload?.secondBestRatio ?? 0,
+      });
     });
 
     offRunning = EventsOn("service:running", (payload) => {
@@ -57,8 +90,10 @@ function App() {
       offPreview?.();
       offStatus?.();
       offRunning?.();
+      offError?.();
+      offReconnected?.();
     };
-  }, [setParams, setStatus, setRunning, reportError]);
+  }, [setParams, setStatus, setRunning, reportError, clearErrorCode]);
 
   const openSettings = () => setScreen("settings");
   const closeSettings = () => setScreen("main");
@@ -97,7 +132,7 @@ function App() {
     <>
       {error && (
         <div className="fixed inset-x-0 top-0 z-50 mx-auto max-w-sm px-5 pt-4">
-          <ErrorBanner message={error} onDismiss={clearError} />
+          <ErrorBanner message={error.message} detail={error.detail} onDismiss={clearError} />
         </div>
       )}
       {screen === "main" ? (
@@ -0,0 +1,23 @@
+import type { FC } from "react";
+
+type CheckboxFieldProps = {
+  label: string;
+  description?: string;
+  checked: boolean;
+  onChange: (checked: boolean) => void;
+};
+
+export const CheckboxField: FC<CheckboxFieldProps> = ({ label, description, checked, onChange }) => (
+  <label className="block text-sm text-zinc-300">
+    <div className="flex items-center gap-3 uppercase tracking-wide">
+      <input
+        type="checkbox"
+        className="h-5 w-5 rounded border border-zinc-700 bg-zinc-900 text-emerald-400 accent-emerald-400 focus:ring-emerald-400"
+        checked={checked}
+        onChange={(event) => onChange(event.target.checked)}
+      />
+      {label}
+    </div>
+    {description && <p className="mt-2 text-xs text-zinc-500">{description}</p>}
+  </label>
+);
@@ -0,0 +1,37 @@
+// Codes emitted by the backend in "app:error" (see internal/app/errors.go).
+export const ERROR_CODES = {
+  cameraOpen: "camera_open_failed",
+  cameraBusy: "camera_busy",
+  cameraRead: "camera_read_failed",
+  pickRejected: "pick_rejected",
+  commandQueueFull: "command_queue_full",
+  recording: "recording_failed",
+  faceNotFound: "face_not_found",
+  faceDetector: "face_detector_failed",
+  headPose: "head_pose_unavailable",
+  calibration: "pose_calibration_failed",
+} as const;
+
+const MESSAGES: Record<string, string> = {
+  [ERROR_CODES.cameraOpen]: "Could not open the camera. Check that it is plugged in and selected in Settings.",
+  [ERROR_CODES.cameraBusy]: "The camera is in use by another application. Close it and start tracking again.",
+  [ERROR_CODES.cameraRead]: "The camera stopped sending video.",
+  [ERROR_CODES.pickRejected]: "That point can't be tracked. Try picking a spot further inside the picture.",
+  [ERROR_CODES.commandQueueFull]: "The app is busy and missed that action. Please try again.",
+  [ERROR_CODES.recording]: "Recording stopped because the session could not be written.",
+  [ERROR_CODES.faceNotFound]: "No face found. Face the camera in good light and try again, or click the preview instead.",
+  [ERROR_CODES.faceDetector]: "Face detection is unavailable. Click the preview to pick a point instead.",
+  [ERROR_CODES.headPose]: "Pointing with the head needs the face model. Check its file in Settings.",
+  [ERROR_CODES.calibration]:
+    "Your head barely moved while calibrating. Turn to point at each edge of the screen, then try again.",
+};
+
+export type BackendError = { code?: string; message?: string };
+
+// describeAppError turns a backend error into the banner text; the backend's
+// own message is kept as the detail line.
+export const describeAppError = (err: BackendError) => ({
+  message: (err.code && MESSAGES[err.code]) || "Something went wrong.",
+  code: err.code,
+  detail: err.message,
+});
@@ -3,20 +3,94 @@ import type { Params } from "../types/params";
 
 export const fromBackendParams = (params: backendConfig.Params): Params => ({
   templateSizePx: params.templateSizePx,
+  scoreThreshold: params.scoreThreshold,
+  searchMargin: params.searchMargin,
+  trackerKind: params.trackerKind,
+  adaptiveTemplate: params.adaptiveTemplate,
+  adaptiveRate: params.adaptiveRate,
+  adaptiveMinScore: params.adaptiveMinScore,
+  scaleMin: params.scaleMin,
+  scaleMax: params.scaleMax,
+  rotationMaxDeg: params.rotationMaxDeg,
+  predictiveSearch: params.predictiveSearch,
+  reacquireAfterFrames: params.reacquireAfterFrames,
   gainMultiplier: params.gainMultiplier,
   smoothing: params.smoothing,
   dwellEnabled: params.dwellEnabled,
   dwellTimeMs: params.dwellTimeMs,
   autoStart: params.autoStart,
+  autoPickFace: params.autoPickFace,
+  pointingMode: params.pointingMode,
+  headPoseModelPath: params.headPoseModelPath,
+  poseYawMin: params.poseYawMin,
+  poseYawMax: params.poseYawMax,
+  posePitchMin: params.posePitchMin,
+  posePitchMax: params.posePitchMax,
   rightClickEnabled: params.rightClickEnabled,
+  cameraDeviceId: params.cameraDeviceId,
+  cameraDevicePath: params.cameraDevicePath,
+  cameraUrl: params.cameraUrl,
+  captureWidth: params.captureWidth,
+  captureHeight: params.captureHeight,
+  captureFps: params.captureFps,
+  capturePixelFormat: params.capturePixelFormat,
+  reconnectTimeoutSec: params.reconnectTimeoutSec,
+  preprocessEqualize: params.preprocessEqualize,
+  preprocessGamma: params.preprocessGamma,
+  preprocessDenoise: params.preprocessDenoise,
+  preprocessNormalize: params.preprocessNormalize,
+  previewProcessed: params.previewProcessed,
+  cropX: params.cropX,
+  cropY: params.cropY,
+  cropWidth: params.cropWidth,
+  cropHeight: params.cropHeight,
+  cameraRotation: params.cameraRotation,
+  mirrorImage: params.mirrorImage,
 });
 
 export const toBackendParams = (params: Params): backendConfig.Params => ({
   templateSizePx: params.templateSizePx,
+  scoreThreshold: params.scoreThreshold,
+  searchMargin: params.searchMargin,
+  trackerKind: params.trackerKind,
+  adaptiveTemplate: params.adaptiveTemplate,
+  adaptiveRate: params.adaptiveRate,
+  adaptiveMinScore: params.adaptiveMinScore,
+  scaleMin: params.scaleMin,
+  scaleMax: params.scaleMax,
+  rotationMaxDeg: params.rotationMaxDeg,
+  predictiveSearch: params.predictiveSearch,
+  reacquireAfterFrames: params.reacquireAfterFrames,
   gainMultiplier: params.gainMultiplier,
   smoothing: params.smoothing,
   dwellEnabled: params.dwellEnabled,
   dwellTimeMs: params.dwellTimeMs,
   autoStart: params.autoStart,
+  autoPickFace: params.autoPickFace,
+  pointingMode: params.pointingMode,
+  headPoseModelPath: params.headPoseModelPath,
+  poseYawMin: params.poseYawMin,
+  poseYawMax: params.poseYawMax,
+  posePitchMin: params.posePitchMin,
+  posePitchMax: params.posePitchMax,
   rightClickEnabled: params.rightClickEnabled,
+  cameraDeviceId: params.cameraDeviceId,
+  cameraDevicePath: params.cameraDevicePath,
+  cameraUrl: params.cameraUrl,
+  captureWidth: params.captureWidth,
+  captureHeight: params.captureHeight,
+  captureFps: params.captureFps,
+  capturePixelFormat: params.capturePixelFormat,
+  reconnectTimeoutSec: params.reconnectTimeoutSec,
+  preprocessEqualize: params.preprocessEqualize,
+  preprocessGamma: params.preprocessGamma,
+  preprocessDenoise: params.preprocessDenoise,
+  preprocessNormalize: params.preprocessNormalize,
+  previewProcessed: params.previewProcessed,
+  cropX: params.cropX,
+  cropY: params.cropY,
+  cropWidth: params.cropWidth,
+  cropHeight: params.cropHeight,
+  cameraRotation: params.cameraRotation,
+  mirrorImage: params.mirrorImage,
 });
@@ -8,7 +8,10 @@ import { CameraPreview } from "./components/CameraPreview";
 import { ClickModeControls } from "./components/ClickModeControls";
 import { PrimaryActions } from "./components/PrimaryActions";
 import { StatusHeader } from "./components/StatusHeader";
+import { useAutoPick } from "./hooks/useAutoPick";
+import { usePoseCalibration } from "./hooks/usePoseCalibration";
 import { useRecenter } from "./hooks/useRecenter";
+import { useRecording } from "./hooks/useRecording";
 
 type MainScreenProps = {
   onOpenSettings: () => void;
@@ -22,6 +25,9 @@ export const MainScreen: FC<MainScreenProps> = ({ onOpenSettings, onStart, onSto
   const { isRunning } = useRunning();
   const { status } = useStatus();
   const { countdown, isRecentering, handleRecenter } = useRecenter();
+  const { autoPick } = useAutoPick();
+  const { countdown: calibrationCountdown, calibrate } = usePoseCalibration();
+  const { sessionDir, toggleRecording } = useRecording(status.recording);
   const [isTransitioning, setIsTransitioning] = useState(false);
 
   const handleStartStop = useCallback(async () => {
@@ -59,15 +65,37 @@ export const MainScreen: FC<MainScreenProps> = ({ onOpenSettings, onStart, onSto
   }, [params, setParamsOptimistic]);
 
   return (
-    <ScreenShell header={<StatusHeader lost={status.lost} onOpenSettings={onOpenSettings} />} mainClassName="gap-4">
+    <ScreenShell
+      header={
+        <StatusHeader
+          lost={status.lost}
+          reacquiring={status.reacquiring}
+          score={status.score}
+          sharpness={status.sharpness}
+          secondBestRatio={status.secondBestRatio}
+          trackerKind={params.trackerKind}
+          camera={status.camera}
+          cameraDisconnected={status.cameraDisconnected}
+          onOpenSettings={onOpenSettings}
+        />
+      }
+      mainClassName="gap-4"
+    >
       <CameraPreview isRecentering={isRecentering} />
       <div className="grid gap-3 text-sm">
         <PrimaryActions
           isRunning={isRunning}
           isTransitioning={isTransitioning}
           recenterCountdown={countdown}
+          pointWithHead={params.pointingMode === "absolute"}
+          calibrationCountdown={calibrationCountdown}
+          isRecording={status.recording}
+          sessionDir={sessionDir}
           onToggleRun={handleStartStop}
           onRecenter={handleRecenter}
+          onAutoPick={autoPick}
+          onCalibrate={calibrate}
+          onToggleRecording={toggleRecording}
         />
         <ClickModeControls
           dwellEnabled={params.dwellEnabled}
@@ -27,6 +27,8 @@ const applyOverlay = (el: HTMLDivElement, transform: CoverTransform | null, trac
   el.style.width = `${boxSize}px`;
   el.style.height = `${boxSize}px`;
   el.style.borderColor = tracking.lost ? "#f87171" : "#34d399";
+  const label = el.firstElementChild as HTMLElement | null;
+  if (label) label.textContent = tracking.score > 0 ? tracking.score.toFixed(2) : "";
 };
 
 export const CameraPreview: FC<CameraPreviewProps> = ({ isRecentering }) => {
@@ -100,7 +102,9 @@ export const CameraPreview: FC<CameraPreviewProps> = ({ isRecentering }) => {
         onClick={onSelectPoint}
       >
         <img ref={imgRef} alt="camera preview" className="absolute inset-0 h-full w-full object-cover" />
-        <div ref={overlayRef} className="pointer-events-none absolute border-2" style={{ display: "none" }} />
+        <div ref={overlayRef} className="pointer-events-none absolute border-2" style={{ display: "none" }}>
+          <span className="absolute left-0 top-full mt-0.5 text-[10px] font-semibold text-white drop-shadow" />
+        </div>
         <div
           ref={recenterBoxRef}
           className="pointer-events-none absolute border-2 border-white"
@@ -5,16 +5,30 @@ type PrimaryActionsProps = {
   isRunning: boolean;
   isTransitioning: boolean;
   recenterCountdown: number;
+  pointWithHead: boolean;
+  calibrationCountdown: number;
+  isRecording: boolean;
+  sessionDir: string | null;
   onToggleRun: () => void;
   onRecenter: () => void;
+  onAutoPick: () => void;
+  onCalibrate: () => void;
+  onToggleRecording: () => void;
 };
 
 export const PrimaryActions: FC<PrimaryActionsProps> = ({
   isRunning,
   isTransitioning,
   recenterCountdown,
+  pointWithHead,
+  calibrationCountdown,
+  isRecording,
+  sessionDir,
   onToggleRun,
   onRecenter,
+  onAutoPick,
+  onCalibrate,
+  onToggleRecording,
 }) => (
   <div className="grid gap-3">
     <Button variant="action" fullWidth onClick={onToggleRun} disabled={isTransitioning}>
@@ -23,5 +37,21 @@ export const PrimaryActions: FC<PrimaryActionsProps> = ({
     <Button fullWidth onClick={onRecenter} disabled={recenterCountdown > 0}>
       {recenterCountdown > 0 ? `Recenter in ${recenterCountdown}` : "Recenter"}
     </Button>
+    <Button fullWidth onClick={onAutoPick} disabled={!isRunning || recenterCountdown > 0}>
+      Pick my face
+    </Button>
+    {pointWithHead && (
+      <Button fullWidth onClick={onCalibrate} disabled={!isRunning || calibrationCountdown > 0}>
+        {calibrationCountdown > 0 ? `Point at each screen edge… ${calibrationCountdown}` : "Calibrate head pointing"}
+      </Button>
+    )}
+    <Button variant="ghost" fullWidth onClick={onToggleRecording} disabled={!isRunning && !isRecording}>
+      {isRecording ? "Stop recording" : "Record session"}
+    </Button>
+    {sessionDir && (
+      <p className="break-all text-center text-[11px] text-zinc-500" title={sessionDir}>
+        {isRecording ? "Recording to" : "Saved to"} {sessionDir}
+      </p>
+    )}
   </div>
 );
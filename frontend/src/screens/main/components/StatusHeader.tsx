@@ -1,19 +1,61 @@
 import type { FC } from "react";
 import { Button } from "../../../components/Button";
 import { cn } from "../../../lib/cn";
+import type { CameraMode } from "../../../state/useStatus";
 
 type StatusHeaderProps = {
   lost: boolean;
+  reacquiring: boolean;
+  score: number;
+  sharpness: number;
+  secondBestRatio: number;
+  trackerKind: string;
+  camera: CameraMode | null;
+  cameraDisconnected: boolean;
   onOpenSettings: () => void;
 };
 
-export const StatusHeader: FC<StatusHeaderProps> = ({ lost, onOpenSettings }) => (
+// Score means something different for each tracker; only the correlation
+// trackers have a peak and runner-up to show.
+const describeScore = (trackerKind: string, score: number, sharpness: number, secondBestRatio: number) => {
+  switch (trackerKind) {
+    case "flow":
+      return `Features kept ${Math.round(score * 100)}%`;
+    case "marker":
+      return `Sticker size match ${score.toFixed(2)}`;
+    default:
+      return `Match ${score.toFixed(2)} · peak ${sharpness.toFixed(1)} · runner-up ${Math.round(secondBestRatio * 100)}%`;
+  }
+};
+
+const describeMode = (mode: CameraMode) =>
+  `${mode.width}×${mode.height} @ ${Math.round(mode.fps)} fps${mode.pixelFormat.trim() ? ` ${mode.pixelFormat}` : ""}`;
+
+export const StatusHeader: FC<StatusHeaderProps> = ({
+  lost,
+  reacquiring,
+  score,
+  sharpness,
+  secondBestRatio,
+  trackerKind,
+  camera,
+  cameraDisconnected,
+  onOpenSettings,
+}) => (
   <header className="flex items-center justify-between rounded-2xl border border-zinc-900 bg-zinc-900 px-4 py-3">
     <div className="text-left">
       <p className="text-[11px] uppercase tracking-[0.2em] text-zinc-400">Open Camera Mouse</p>
-      <p className={cn("text-base font-semibold", lost ? "text-red-400" : "text-emerald-400")}>
-        {lost ? "LOST" : "OK"}
-      </p>
+      {cameraDisconnected ? (
+        <p className="text-base font-semibold text-amber-400">RECONNECTING CAMERA…</p>
+      ) : (
+        <p className={cn("text-base font-semibold", lost ? "text-red-400" : "text-emerald-400")}>
+          {lost ? (reacquiring ? "LOST — SEARCHING…" : "LOST") : "OK"}
+        </p>
+      )}
+      {!cameraDisconnected && score > 0 && (
+        <p className="text-[11px] text-zinc-500">{describeScore(trackerKind, score, sharpness, secondBestRatio)}</p>
+      )}
+      {camera && <p className="text-[11px] text-zinc-500">{describeMode(camera)}</p>}
     </div>
     <Button onClick={onOpenSettings}>Settings</Button>
   </header>
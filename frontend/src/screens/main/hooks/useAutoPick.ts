@@ -0,0 +1,24 @@
+import { useCallback } from "react";
+import { AutoPick } from "../../../../wailsjs/go/main/App";
+import { useAppError } from "../../../state/useAppError";
+
+/**
+ * Asks the backend to pick the user's face as the tracking target. The
+ * search runs over the next frames; if no face turns up the backend reports
+ * face_not_found through app:error.
+ */
+export const useAutoPick = () => {
+  const { reportError, clearError } = useAppError();
+
+  const autoPick = useCallback(async () => {
+    try {
+      await AutoPick();
+      clearError();
+    } catch (err) {
+      console.error("auto pick failed", err);
+      reportError("Could not look for your face — is tracking running?");
+    }
+  }, [reportError, clearError]);
+
+  return { autoPick };
+};
@@ -0,0 +1,49 @@
+import { useCallback, useRef } from "react";
+import { BeginPoseCalibration, FinishPoseCalibration } from "../../../../wailsjs/go/main/App";
+import { useAppError } from "../../../state/useAppError";
+import { useParams } from "../../../state/useParams";
+import { fromBackendParams } from "../../../lib/params";
+import { useRecenterCountdown } from "./useRecenterCountdown";
+
+const CALIBRATION_SECONDS = 6;
+
+/**
+ * Calibrates absolute pointing: the backend records head poses while the
+ * user turns to point at each screen edge during a visible countdown, then
+ * saves the range it saw and returns the updated params. Too small a range
+ * is reported as pose_calibration_failed through app:error.
+ */
+export const usePoseCalibration = () => {
+  const calibratingRef = useRef(false);
+  const { setParams } = useParams();
+  const { reportError, clearError } = useAppError();
+  const { value: countdown, start: startCountdown } = useRecenterCountdown();
+
+  const calibrate = useCallback(async () => {
+    if (calibratingRef.current) return;
+
+    try {
+      await BeginPoseCalibration();
+    } catch (err) {
+      console.error("begin pose calibration failed", err);
+      reportError("Could not start calibrating — is tracking running?");
+      return;
+    }
+
+    calibratingRef.current = true;
+    clearError();
+
+    startCountdown(CALIBRATION_SECONDS, async () => {
+      try {
+        const saved = await FinishPoseCalibration();
+        setParams(fromBackendParams(saved));
+      } catch (err) {
+        console.error("finish pose calibration failed", err);
+      } finally {
+        calibratingRef.current = false;
+      }
+    });
+  }, [setParams, reportError, clearError, startCountdown]);
+
+  return { countdown, calibrate };
+};
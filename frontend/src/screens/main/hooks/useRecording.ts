@@ -0,0 +1,30 @@
+import { useCallback, useState } from "react";
+import { StartRecording, StopRecording } from "../../../../wailsjs/go/main/App";
+import { useAppError } from "../../../state/useAppError";
+
+/**
+ * Starts/stops a session recording. Whether one is in progress comes from
+ * status.recording, so a recording ended by the backend (Stop, disk full)
+ * is reflected without extra bookkeeping here; sessionDir only remembers
+ * where the last one went.
+ */
+export const useRecording = (isRecording: boolean) => {
+  const [sessionDir, setSessionDir] = useState<string | null>(null);
+  const { reportError, clearError } = useAppError();
+
+  const toggleRecording = useCallback(async () => {
+    try {
+      if (isRecording) {
+        await StopRecording();
+      } else {
+        setSessionDir(await StartRecording());
+      }
+      clearError();
+    } catch (err) {
+      console.error("recording toggle failed", err);
+      reportError(isRecording ? "Could not stop recording." : "Could not start recording — is tracking running?");
+    }
+  }, [isRecording, reportError, clearError]);
+
+  return { sessionDir, toggleRecording };
+};
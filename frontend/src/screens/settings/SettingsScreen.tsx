@@ -3,12 +3,59 @@ import { Button } from "../../components/Button";
 import { ScreenShell } from "../../components/ScreenShell";
 import { ChoiceButton } from "../../components/ChoiceButton";
 import { SliderField } from "../../components/SliderField";
+import { CheckboxField } from "../../components/CheckboxField";
 import { defaultParams } from "../../state/useParams";
 import { useSettingsDraft } from "../../state/useSettingsDraft";
 import type { Params } from "../../types/params";
 import { deepClone } from "../../lib/clone";
+import { CameraPicker } from "./components/CameraPicker";
+import { useHeadPoseAvailable } from "./hooks/useHeadPoseAvailable";
 
 const TEMPLATE_SIZES = [30, 45, 60];
+const TRACKER_KINDS = [
+  { value: "template", label: "Patch match" },
+  { value: "flow", label: "Feature flow" },
+  { value: "constellation", label: "Several patches" },
+  { value: "marker", label: "Colored sticker" },
+];
+const POINTING_MODES = [
+  { value: "relative", label: "Follow movement" },
+  { value: "absolute", label: "Point with head" },
+];
+const CAPTURE_RESOLUTIONS = [
+  { label: "Auto", width: 0, height: 0 },
+  { label: "640×480", width: 640, height: 480 },
+  { label: "1280×720", width: 1280, height: 720 },
+];
+const ROTATIONS = [0, 90, 180, 270];
+// Template scale range relative to the picked patch, for leaning in and out.
+const ROTATION_LIMITS = [0, 10, 20];
+// Lost frames before searching the whole frame; labels assume 30 fps.
+const REACQUIRE_DELAYS = [
+  { label: "Off", frames: 0 },
+  { label: "0.5 s", frames: 15 },
+  { label: "1 s", frames: 30 },
+  { label: "2 s", frames: 60 },
+];
+const SCALE_RANGES = [
+  { label: "Off", min: 1, max: 1 },
+  { label: "Small", min: 0.9, max: 1.1 },
+  { label: "Wide", min: 0.8, max: 1.25 },
+];
+const CAPTURE_FPS = [0, 15, 30, 60];
+const PIXEL_FORMATS = ["", "MJPG", "YUYV"];
+// Digital zoom is a centered crop: zoom z keeps the middle 1/z of the frame.
+const zoomToCrop = (zoom: number) => {
+  const size = 1 / zoom;
+  const offset = (1 - size) / 2;
+  return { cropX: offset, cropY: offset, cropWidth: size, cropHeight: size };
+};
+
+const EQUALIZE_MODES = [
+  { label: "Off", value: "" },
+  { label: "Histogram", value: "histogram" },
+  { label: "CLAHE", value: "clahe" },
+];
 
 type SettingsScreenProps = {
   onSave: (params: Params) => Promise<void>;
@@ -18,6 +65,7 @@ type SettingsScreenProps = {
 export const SettingsScreen: FC<SettingsScreenProps> = ({ onSave, onCancel }) => {
   const { draft, dirty, update, updateDraft, resetDraft } = useSettingsDraft();
   const [saving, setSaving] = useState(false);
+  const headPoseAvailable = useHeadPoseAvailable(draft.headPoseModelPath);
 
   const handleCancel = () => {
     resetDraft();
@@ -69,6 +117,109 @@ export const SettingsScreen: FC<SettingsScreenProps> = ({ onSave, onCancel }) =>
     >
       <div className="flex-1 overflow-auto rounded-2xl border border-zinc-900 bg-zinc-950 px-4 py-4">
         <div className="space-y-6 text-sm text-zinc-300">
+          <CameraPicker
+            deviceId={draft.cameraDeviceId}
+            devicePath={draft.cameraDevicePath}
+            url={draft.cameraUrl}
+            onSelect={(cameraDeviceId, cameraDevicePath) => update({ cameraDeviceId, cameraDevicePath, cameraUrl: "" })}
+            onUrlChange={(cameraUrl) => update({ cameraUrl })}
+          />
+
+          <div>
+            <p className="mb-2 text-xs font-semibold uppercase tracking-wide text-zinc-400">Capture resolution</p>
+            <div className="flex gap-2">
+              {CAPTURE_RESOLUTIONS.map((res) => (
+                <ChoiceButton
+                  key={res.label}
+                  selected={draft.captureWidth === res.width && draft.captureHeight === res.height}
+                  onClick={() => update({ captureWidth: res.width, captureHeight: res.height })}
+                >
+                  {res.label}
+                </ChoiceButton>
+              ))}
+            </div>
+          </div>
+
+          <div>
+            <p className="mb-2 text-xs font-semibold uppercase tracking-wide text-zinc-400">Capture frame rate</p>
+            <div className="flex gap-2">
+              {CAPTURE_FPS.map((fps) => (
+                <ChoiceButton key={fps} selected={draft.captureFps === fps} onClick={() => update({ captureFps: fps })}>
+                  {fps === 0 ? "Auto" : `${fps}`}
+                </ChoiceButton>
+              ))}
+            </div>
+          </div>
+
+          <div>
+            <p className="mb-2 text-xs font-semibold uppercase tracking-wide text-zinc-400">Pixel format</p>
+            <div className="flex gap-2">
+              {PIXEL_FORMATS.map((format) => (
+                <ChoiceButton
+                  key={format || "auto"}
+                  selected={draft.capturePixelFormat === format}
+                  onClick={() => update({ capturePixelFormat: format })}
+                >
+                  {format || "Auto"}
+                </ChoiceButton>
+              ))}
+            </div>
+          </div>
+
+          <div>
+            <p className="mb-2 text-xs font-semibold uppercase tracking-wide text-zinc-400">Camera rotation</p>
+            <div className="flex gap-2">
+              {ROTATIONS.map((rotation) => (
+                <ChoiceButton
+                  key={rotation}
+                  selected={draft.cameraRotation === rotation}
+                  onClick={() => update({ cameraRotation: rotation })}
+                >
+                  {rotation}°
+                </ChoiceButton>
+              ))}
+            </div>
+          </div>
+
+          <CheckboxField
+            label="Mirror image"
+            description="On for a webcam facing you. Turn off if your camera already shows a mirrored picture."
+            checked={draft.mirrorImage}
+            onChange={(mirrorImage) => update({ mirrorImage })}
+          />
+
+          <SliderField
+            label={`Digital zoom (${(1 / draft.cropWidth).toFixed(1)}x)`}
+            min={1}
+            max={3}
+            step={0.1}
+            value={1 / draft.cropWidth}
+            onChange={(value) => update(zoomToCrop(value))}
+          />
+
+          <div>
+            <p className="mb-2 text-xs font-semibold uppercase tracking-wide text-zinc-400">Tracking method</p>
+            <div className="flex gap-2">
+              {TRACKER_KINDS.map((kind) => (
+                <ChoiceButton
+                  key={kind.value}
+                  selected={draft.trackerKind === kind.value}
+                  onClick={() => update({ trackerKind: kind.value })}
+                >
+                  {kind.label}
+                </ChoiceButton>
+              ))}
+            </div>
+            <p className="mt-2 text-xs text-zinc-500">
+              Feature flow follows many small corners around the point and often works better on faces with little
+              texture. Several patches also tracks spots between your eyes and beside your nose, and keeps going when a
+              hand or glare covers some of them. The threshold, search area, distance, tilt, prediction, re-acquire and
+              adaptive options don't apply to feature flow. Colored sticker follows a sticker or reflective dot you
+              wear: click on it to pick its color. Only the search area and re-acquire apply to it, and image
+              adjustments are skipped.
+            </p>
+          </div>
+
           <div>
             <p className="mb-2 text-xs font-semibold uppercase tracking-wide text-zinc-400">Template size</p>
             <div className="flex gap-2">
@@ -84,12 +235,158 @@ export const SettingsScreen: FC<SettingsScreenProps> = ({ onSave, onCancel }) =>
             </div>
           </div>
 
+          <SliderField
+            label={`Match threshold (${draft.scoreThreshold.toFixed(2)})`}
+            min={0.3}
+            max={0.95}
+            step={0.01}
+            value={draft.scoreThreshold}
+            onChange={(value) => update({ scoreThreshold: value })}
+          />
+
+          <SliderField
+            label={`Search area (${draft.searchMargin.toFixed(1)}× template)`}
+            min={1}
+            max={5}
+            step={0.5}
+            value={draft.searchMargin}
+            onChange={(value) => update({ searchMargin: value })}
+          />
+
+          <div>
+            <p className="mb-2 text-xs font-semibold uppercase tracking-wide text-zinc-400">Distance changes</p>
+            <div className="flex gap-2">
+              {SCALE_RANGES.map((range) => (
+                <ChoiceButton
+                  key={range.label}
+                  selected={draft.scaleMin === range.min && draft.scaleMax === range.max}
+                  onClick={() => update({ scaleMin: range.min, scaleMax: range.max })}
+                >
+                  {range.label}
+                </ChoiceButton>
+              ))}
+            </div>
+            <p className="mt-2 text-xs text-zinc-500">
+              Keep tracking when you lean toward or away from the camera. Wider ranges use more CPU.
+            </p>
+          </div>
+
+          <div>
+            <p className="mb-2 text-xs font-semibold uppercase tracking-wide text-zinc-400">Head tilt</p>
+            <div className="flex gap-2">
+              {ROTATION_LIMITS.map((limit) => (
+                <ChoiceButton
+                  key={limit}
+                  selected={draft.rotationMaxDeg === limit}
+                  onClick={() => update({ rotationMaxDeg: limit })}
+                >
+                  {limit === 0 ? "Off" : `±${limit}°`}
+                </ChoiceButton>
+              ))}
+            </div>
+            <p className="mt-2 text-xs text-zinc-500">Keep tracking when you tilt your head sideways.</p>
+          </div>
+
+          <div>
+            <p className="mb-2 text-xs font-semibold uppercase tracking-wide text-zinc-400">Find me again after</p>
+            <div className="flex gap-2">
+              {REACQUIRE_DELAYS.map((delay) => (
+                <ChoiceButton
+                  key={delay.frames}
+                  selected={draft.reacquireAfterFrames === delay.frames}
+                  onClick={() => update({ reacquireAfterFrames: delay.frames })}
+                >
+                  {delay.label}
+                </ChoiceButton>
+              ))}
+            </div>
+            <p className="mt-2 text-xs text-zinc-500">
+              When tracking is lost this long, search the whole picture and pick the point up again once it is clearly
+              back.
+            </p>
+          </div>
+
+          <CheckboxField
+            label="Predict fast movement"
+            description="Search where your head is heading rather than where it was, and keep the cursor gliding through a brief loss."
+            checked={draft.predictiveSearch}
+            onChange={(predictiveSearch) => update({ predictiveSearch })}
+          />
+
+          <CheckboxField
+            label="Adaptive template"
+            description="Slowly update the tracked patch to follow lighting changes and head turns. Stays anchored to the point you picked."
+            checked={draft.adaptiveTemplate}
+            onChange={(adaptiveTemplate) => update({ adaptiveTemplate })}
+          />
+
+          <SliderField
+            label={`Adaptation rate (${Math.round(draft.adaptiveRate * 100)}%)`}
+            min={1}
+            max={20}
+            step={1}
+            value={Math.round(draft.adaptiveRate * 100)}
+            disabled={!draft.adaptiveTemplate}
+            onChange={(value) => update({ adaptiveRate: value / 100 })}
+          />
+
+          <SliderField
+            label={`Adapt above match score (${draft.adaptiveMinScore.toFixed(2)})`}
+            min={0.7}
+            max={0.99}
+            step={0.01}
+            value={draft.adaptiveMinScore}
+            disabled={!draft.adaptiveTemplate}
+            onChange={(value) => update({ adaptiveMinScore: value })}
+          />
+
+          <div>
+            <p className="mb-2 text-xs font-semibold uppercase tracking-wide text-zinc-400">Pointing</p>
+            <div className="flex gap-2">
+              {POINTING_MODES.map((mode) => (
+                <ChoiceButton
+                  key={mode.value}
+                  selected={draft.pointingMode === mode.value}
+                  disabled={mode.value === "absolute" && headPoseAvailable !== true}
+                  onClick={() => update({ pointingMode: mode.value })}
+                >
+                  {mode.label}
+                </ChoiceButton>
+              ))}
+            </div>
+            <p className="mt-2 text-xs text-zinc-500">
+              Point with head places the cursor where your face points, from how far you turn and tilt it. Calibrate it
+              from the main screen.
+            </p>
+            {headPoseAvailable === false && (
+              <p className="mt-2 text-xs text-amber-400">
+                {draft.headPoseModelPath
+                  ? "No face model at the path below, so pointing with the head is unavailable."
+                  : "This build has no face model, so pointing with the head needs one set below."}
+              </p>
+            )}
+            <label className="mt-3 block">
+              <span className="mb-1 block text-xs text-zinc-500">
+                Face model override (face_detection_yunet .onnx file; leave empty for the built-in one)
+              </span>
+              <input
+                type="text"
+                value={draft.headPoseModelPath}
+                placeholder="Built-in model"
+                spellCheck={false}
+                className="w-full rounded-lg border border-zinc-800 bg-zinc-900 px-3 py-2 text-sm text-zinc-100 placeholder:text-zinc-600 focus:border-emerald-400 focus:outline-none"
+                onChange={(event) => update({ headPoseModelPath: event.target.value.trim() })}
+              />
+            </label>
+          </div>
+
           <SliderField
             label={`Gain (${draft.gainMultiplier.toFixed(1)}x)`}
             min={1}
             max={30}
             step={0.5}
             value={draft.gainMultiplier}
+            disabled={draft.pointingMode === "absolute"}
             onChange={(value) => update({ gainMultiplier: value })}
           />
 
@@ -111,18 +408,64 @@ export const SettingsScreen: FC<SettingsScreenProps> = ({ onSave, onCancel }) =>
             onChange={(value) => update({ dwellTimeMs: value })}
           />
 
-          <label className="block text-sm text-zinc-300">
-            <div className="flex items-center gap-3 uppercase tracking-wide">
-              <input
-                type="checkbox"
-                className="h-5 w-5 rounded border border-zinc-700 bg-zinc-900 text-emerald-400 accent-emerald-400 focus:ring-emerald-400"
-                checked={draft.autoStart}
-                onChange={(event) => update({ autoStart: event.target.checked })}
-              />
-              Autostart camera
+          <div>
+            <p className="mb-2 text-xs font-semibold uppercase tracking-wide text-zinc-400">Contrast equalization</p>
+            <div className="flex gap-2">
+              {EQUALIZE_MODES.map((mode) => (
+                <ChoiceButton
+                  key={mode.label}
+                  selected={draft.preprocessEqualize === mode.value}
+                  onClick={() => update({ preprocessEqualize: mode.value })}
+                >
+                  {mode.label}
+                </ChoiceButton>
+              ))}
             </div>
-            <p className="mt-2 text-xs text-zinc-500">Begin capturing automatically when the app launches.</p>
-          </label>
+          </div>
+
+          <SliderField
+            label={`Gamma (${draft.preprocessGamma.toFixed(1)})`}
+            min={0.5}
+            max={2.5}
+            step={0.1}
+            value={draft.preprocessGamma}
+            onChange={(value) => update({ preprocessGamma: value })}
+          />
+
+          <CheckboxField
+            label="Normalize brightness"
+            description="Scale each frame toward a mid-grey average — helps in dim rooms."
+            checked={draft.preprocessNormalize}
+            onChange={(preprocessNormalize) => update({ preprocessNormalize })}
+          />
+
+          <CheckboxField
+            label="Reduce noise"
+            description="Median filter before tracking; useful for grainy low-light images."
+            checked={draft.preprocessDenoise}
+            onChange={(preprocessDenoise) => update({ preprocessDenoise })}
+          />
+
+          <CheckboxField
+            label="Preview processed image"
+            description="Show the image the tracker sees instead of the camera feed."
+            checked={draft.previewProcessed}
+            onChange={(previewProcessed) => update({ previewProcessed })}
+          />
+
+          <CheckboxField
+            label="Autostart camera"
+            description="Begin capturing automatically when the app launches."
+            checked={draft.autoStart}
+            onChange={(autoStart) => update({ autoStart })}
+          />
+
+          <CheckboxField
+            label="Pick my face automatically"
+            description="Recenter and autostart track the bridge of your nose, found by face detection, instead of the middle of the picture."
+            checked={draft.autoPickFace}
+            onChange={(autoPickFace) => update({ autoPickFace })}
+          />
         </div>
       </div>
     </ScreenShell>
@@ -0,0 +1,72 @@
+import { useEffect, useState, type FC } from "react";
+import { ListCameras } from "../../../../wailsjs/go/main/App";
+import type { camera } from "../../../../wailsjs/go/models";
+import { ChoiceButton } from "../../../components/ChoiceButton";
+
+type CameraPickerProps = {
+  deviceId: number;
+  devicePath: string;
+  url: string;
+  onSelect: (deviceId: number, devicePath: string) => void;
+  onUrlChange: (url: string) => void;
+};
+
+const isSelected = (device: camera.Device, deviceId: number, devicePath: string) =>
+  devicePath ? device.path === devicePath : device.id === deviceId;
+
+const describe = (device: camera.Device) => {
+  const best = device.resolutions?.[device.resolutions.length - 1];
+  const mode = best ? ` · up to ${best.width}×${best.height}` : "";
+  return `Camera ${device.id} (${device.backend})${mode}`;
+};
+
+export const CameraPicker: FC<CameraPickerProps> = ({ deviceId, devicePath, url, onSelect, onUrlChange }) => {
+  const [devices, setDevices] = useState<camera.Device[] | null>(null);
+
+  useEffect(() => {
+    let cancelled = false;
+    ListCameras()
+      .then((res) => {
+        if (!cancelled) setDevices(res ?? []);
+      })
+      .catch((err) => {
+        console.error("failed to list cameras", err);
+        if (!cancelled) setDevices([]);
+      });
+    return () => {
+      cancelled = true;
+    };
+  }, []);
+
+  return (
+    <div>
+      <p className="mb-2 text-xs font-semibold uppercase tracking-wide text-zinc-400">Camera</p>
+      {devices === null && <p className="text-xs text-zinc-500">Looking for cameras…</p>}
+      {devices?.length === 0 && <p className="text-xs text-zinc-500">No cameras found.</p>}
+      <div className="flex flex-col gap-2">
+        {devices?.map((device) => (
+          <ChoiceButton
+            key={`${device.id}:${device.path ?? ""}`}
+            selected={!url && isSelected(device, deviceId, devicePath)}
+            onClick={() => onSelect(device.id, device.path ?? "")}
+          >
+            {describe(device)}
+          </ChoiceButton>
+        ))}
+      </div>
+      <label className="mt-3 block">
+        <span className="mb-1 block text-xs text-zinc-500">
+          Network camera (MJPEG over HTTP or RTSP) — used instead of the cameras above when set
+        </span>
+        <input
+          type="url"
+          value={url}
+          placeholder="rtsp://192.168.1.20:8554/live"
+          spellCheck={false}
+          className="w-full rounded-lg border border-zinc-800 bg-zinc-900 px-3 py-2 text-sm text-zinc-100 placeholder:text-zinc-600 focus:border-emerald-400 focus:outline-none"
+          onChange={(event) => onUrlChange(event.target.value.trim())}
+        />
+      </label>
+    </div>
+  );
+};
@@ -0,0 +1,29 @@
+import { useEffect, useState } from "react";
+import { HeadPoseAvailable } from "../../../../wailsjs/go/main/App";
+
+/**
+ * Whether pointing with the head has a face model to load: the file at
+ * modelPath, or the built-in model when it is empty. null until the backend
+ * has answered for the current path.
+ */
+export const useHeadPoseAvailable = (modelPath: string) => {
+  const [available, setAvailable] = useState<boolean | null>(null);
+
+  useEffect(() => {
+    let cancelled = false;
+    setAvailable(null);
+    HeadPoseAvailable(modelPath)
+      .then((res) => {
+        if (!cancelled) setAvailable(res);
+      })
+      .catch((err) => {
+        console.error("failed to check the face model", err);
+        if (!cancelled) setAvailable(false);
+      });
+    return () => {
+      cancelled = true;
+    };
+  }, [modelPath]);
+
+  return available;
+};
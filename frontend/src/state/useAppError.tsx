@@ -1,25 +1,42 @@
 import { createContext, useCallback, useContext, useState, type FC, type ReactNode } from "react";
 
+export type AppError = {
+  message: string;
+  // Backend error code from "app:error"; undefined for errors raised by the UI itself.
+  code?: string;
+  detail?: string;
+};
+
 type AppErrorContextValue = {
-  error: string | null;
-  reportError: (message: string) => void;
+  error: AppError | null;
+  reportError: (message: string, code?: string, detail?: string) => void;
   clearError: () => void;
+  // Clears the banner only if it is still showing an error with this code.
+  clearErrorCode: (code: string) => void;
 };
 
 const AppErrorContext = createContext<AppErrorContextValue | undefined>(undefined);
 
 export const AppErrorProvider: FC<{ children: ReactNode }> = ({ children }) => {
-  const [error, setError] = useState<string | null>(null);
+  const [error, setError] = useState<AppError | null>(null);
 
-  const reportError = useCallback((message: string) => {
-    setError(message);
+  const reportError = useCallback((message: string, code?: string, detail?: string) => {
+    setError({ message, code, detail });
   }, []);
 
   const clearError = useCallback(() => {
     setError(null);
   }, []);
 
-  return <AppErrorContext.Provider value={{ error, reportError, clearError }}>{children}</AppErrorContext.Provider>;
+  const clearErrorCode = useCallback((code: string) => {
+    setError((prev) => (prev?.code === code ? null : prev));
+  }, []);
+
+  return (
+    <AppErrorContext.Provider value={{ error, reportError, clearError, clearErrorCode }}>
+      {children}
+    </AppErrorContext.Provider>
+  );
 };
 
 export const useAppError = (): AppErrorContextValue => {
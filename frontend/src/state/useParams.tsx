@@ -3,12 +3,49 @@ import type { Params } from "../types/params";
 
 export const defaultParams: Params = {
   templateSizePx: 45,
+  scoreThreshold: 0.68,
+  searchMargin: 2,
+  trackerKind: "template",
+  adaptiveTemplate: false,
+  adaptiveRate: 0.05,
+  adaptiveMinScore: 0.85,
+  scaleMin: 1,
+  scaleMax: 1,
+  rotationMaxDeg: 0,
+  predictiveSearch: false,
+  reacquireAfterFrames: 15,
   gainMultiplier: 8.0,
   smoothing: 0.3,
   dwellEnabled: false,
   dwellTimeMs: 500,
   autoStart: false,
+  autoPickFace: false,
+  pointingMode: "relative",
+  headPoseModelPath: "",
+  poseYawMin: -20,
+  poseYawMax: 20,
+  posePitchMin: -12,
+  posePitchMax: 12,
   rightClickEnabled: false,
+  cameraDeviceId: 0,
+  cameraDevicePath: "",
+  cameraUrl: "",
+  captureWidth: 0,
+  captureHeight: 0,
+  captureFps: 0,
+  capturePixelFormat: "",
+  reconnectTimeoutSec: 60,
+  preprocessEqualize: "",
+  preprocessGamma: 1.0,
+  preprocessDenoise: false,
+  preprocessNormalize: false,
+  previewProcessed: false,
+  cropX: 0,
+  cropY: 0,
+  cropWidth: 1,
+  cropHeight: 1,
+  cameraRotation: 0,
+  mirrorImage: true,
 };
 
 type ParamsContextValue = {
@@ -1,7 +1,21 @@
 import { createContext, useCallback, useContext, useState, type FC, type ReactNode } from "react";
 
+export type CameraMode = {
+  width: number;
+  height: number;
+  fps: number;
+  pixelFormat: string;
+};
+
 export type Status = {
   lost: boolean;
+  reacquiring: boolean;
+  camera: CameraMode | null;
+  cameraDisconnected: boolean;
+  recording: boolean;
+  score: number;
+  sharpness: number;
+  secondBestRatio: number;
 };
 
 type StatusContextValue = {
@@ -12,7 +26,16 @@ type StatusContextValue = {
 const StatusContext = createContext<StatusContextValue | undefined>(undefined);
 
 export const StatusProvider: FC<{ children: ReactNode }> = ({ children }) => {
-  const [status, setStatusState] = useState<Status>({ lost: false });
+  const [status, setStatusState] = useState<Status>({
+    lost: false,
+    reacquiring: false,
+    camera: null,
+    cameraDisconnected: false,
+    recording: false,
+    score: 0,
+    sharpness: 0,
+    secondBestRatio: 0,
+  });
 
   const setStatus = useCallback((next: Status) => {
     setStatusState(next);
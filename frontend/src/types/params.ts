@@ -1,9 +1,46 @@
 export type Params = {
   templateSizePx: number;
+  scoreThreshold: number;
+  searchMargin: number;
+  trackerKind: string;
+  adaptiveTemplate: boolean;
+  adaptiveRate: number;
+  adaptiveMinScore: number;
+  scaleMin: number;
+  scaleMax: number;
+  rotationMaxDeg: number;
+  predictiveSearch: boolean;
+  reacquireAfterFrames: number;
   gainMultiplier: number;
   smoothing: number;
   dwellEnabled: boolean;
   dwellTimeMs: number;
   autoStart: boolean;
+  autoPickFace: boolean;
+  pointingMode: string;
+  headPoseModelPath: string;
+  poseYawMin: number;
+  poseYawMax: number;
+  posePitchMin: number;
+  posePitchMax: number;
   rightClickEnabled: boolean;
+  cameraDeviceId: number;
+  cameraDevicePath: string;
+  cameraUrl: string;
+  captureWidth: number;
+  captureHeight: number;
+  captureFps: number;
+  capturePixelFormat: string;
+  reconnectTimeoutSec: number;
+  preprocessEqualize: string;
+  preprocessGamma: number;
+  preprocessDenoise: boolean;
+  preprocessNormalize: boolean;
+  previewProcessed: boolean;
+  cropX: number;
+  cropY: number;
+  cropWidth: number;
+  cropHeight: number;
+  cameraRotation: number;
+  mirrorImage: boolean;
 };
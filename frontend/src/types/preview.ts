@@ -3,6 +3,9 @@ export type TrackingOverlay = {
   y: number;
   templateSizePx: number;
   lost: boolean;
+  score: number;
+  sharpness: number;
+  secondBestRatio: number;
 };
 
 export type PreviewFrame = {
@@ -1,21 +1,41 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 import {config} from '../models';
+import {app} from '../models';
+import {camera} from '../models';
+
+export function AutoPick():Promise<void>;
+
+export function BeginPoseCalibration():Promise<void>;
 
 export function BeginRecenter():Promise<void>;
 
 export function ConfirmRecenter():Promise<void>;
 
+export function FinishPoseCalibration():Promise<config.Params>;
+
 export function GetParams():Promise<config.Params>;
 
+export function HeadPoseAvailable(arg1:string):Promise<boolean>;
+
+export function LastError():Promise<app.Error>;
+
+export function ListCameras():Promise<Array<camera.Device>>;
+
 export function PickPoint(arg1:number,arg2:number):Promise<void>;
 
 export function ResetMouse():Promise<void>;
 
+export function SelectCamera(arg1:number,arg2:string):Promise<void>;
+
 export function Start():Promise<void>;
 
+export function StartRecording():Promise<string>;
+
 export function Stop():Promise<void>;
 
+export function StopRecording():Promise<void>;
+
 export function ToggleTracking(arg1:boolean):Promise<void>;
 
 export function UpdateParams(arg1:config.Params):Promise<void>;
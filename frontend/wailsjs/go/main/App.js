@@ -2,6 +2,14 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function AutoPick() {
+  return window['go']['main']['App']['AutoPick']();
+}
+
+export function BeginPoseCalibration() {
+  return window['go']['main']['App']['BeginPoseCalibration']();
+}
+
 export function BeginRecenter() {
   return window['go']['main']['App']['BeginRecenter']();
 }
@@ -10,10 +18,26 @@ export function ConfirmRecenter() {
   return window['go']['main']['App']['ConfirmRecenter']();
 }
 
+export function FinishPoseCalibration() {
+  return window['go']['main']['App']['FinishPoseCalibration']();
+}
+
 export function GetParams() {
   return window['go']['main']['App']['GetParams']();
 }
 
+export function HeadPoseAvailable(arg1) {
+  return window['go']['main']['App']['HeadPoseAvailable'](arg1);
+}
+
+export function LastError() {
+  return window['go']['main']['App']['LastError']();
+}
+
+export function ListCameras() {
+  return window['go']['main']['App']['ListCameras']();
+}
+
 export function PickPoint(arg1, arg2) {
   return window['go']['main']['App']['PickPoint'](arg1, arg2);
 }
@@ -22,14 +46,26 @@ export function ResetMouse() {
   return window['go']['main']['App']['ResetMouse']();
 }
 
+export function SelectCamera(arg1, arg2) {
+  return window['go']['main']['App']['SelectCamera'](arg1, arg2);
+}
+
 export function Start() {
   return window['go']['main']['App']['Start']();
 }
 
+export function StartRecording() {
+  return window['go']['main']['App']['StartRecording']();
+}
+
 export function Stop() {
   return window['go']['main']['App']['Stop']();
 }
 
+export function StopRecording() {
+  return window['go']['main']['App']['StopRecording']();
+}
+
 export function ToggleTracking(arg1) {
   return window['go']['main']['App']['ToggleTracking'](arg1);
 }
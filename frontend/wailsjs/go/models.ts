@@ -1,13 +1,145 @@
+export namespace app {
+	
+	export class Error {
+	    code: string;
+	    message: string;
+	    // Go type: time
+	    at: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new Error(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.code = source["code"];
+	        this.message = source["message"];
+	        this.at = this.convertValues(source["at"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace camera {
+	
+	export class Resolution {
+	    width: number;
+	    height: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Resolution(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.width = source["width"];
+	        this.height = source["height"];
+	    }
+	}
+	export class Device {
+	    id: number;
+	    path?: string;
+	    backend: string;
+	    resolutions?: Resolution[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Device(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.path = source["path"];
+	        this.backend = source["backend"];
+	        this.resolutions = this.convertValues(source["resolutions"], Resolution);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
 export namespace config {
 	
 	export class Params {
+	    trackerKind: string;
 	    templateSizePx: number;
+	    scoreThreshold: number;
+	    searchMargin: number;
+	    adaptiveTemplate: boolean;
+	    adaptiveRate: number;
+	    adaptiveMinScore: number;
+	    scaleMin: number;
+	    scaleMax: number;
+	    rotationMaxDeg: number;
+	    predictiveSearch: boolean;
+	    reacquireAfterFrames: number;
+	    autoPickFace: boolean;
+	    pointingMode: string;
+	    headPoseModelPath: string;
+	    poseYawMin: number;
+	    poseYawMax: number;
+	    posePitchMin: number;
+	    posePitchMax: number;
 	    gainMultiplier: number;
 	    smoothing: number;
 	    dwellEnabled: boolean;
 	    dwellTimeMs: number;
 	    autoStart: boolean;
 	    rightClickEnabled: boolean;
+	    cameraDeviceId: number;
+	    cameraDevicePath: string;
+	    cameraUrl: string;
+	    captureWidth: number;
+	    captureHeight: number;
+	    captureFps: number;
+	    capturePixelFormat: string;
+	    reconnectTimeoutSec: number;
+	    preprocessEqualize: string;
+	    preprocessGamma: number;
+	    preprocessDenoise: boolean;
+	    preprocessNormalize: boolean;
+	    previewProcessed: boolean;
+	    cropX: number;
+	    cropY: number;
+	    cropWidth: number;
+	    cropHeight: number;
+	    cameraRotation: number;
+	    mirrorImage: boolean;
 	
 	    static createFrom(source: any = {}) {
 	        return new Params(source);
@@ -15,13 +147,50 @@ export namespace config {
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.trackerKind = source["trackerKind"];
 	        this.templateSizePx = source["templateSizePx"];
+	        this.scoreThreshold = source["scoreThreshold"];
+	        this.searchMargin = source["searchMargin"];
+	        this.adaptiveTemplate = source["adaptiveTemplate"];
+	        this.adaptiveRate = source["adaptiveRate"];
+	        this.adaptiveMinScore = source["adaptiveMinScore"];
+	        this.scaleMin = source["scaleMin"];
+	        this.scaleMax = source["scaleMax"];
+	        this.rotationMaxDeg = source["rotationMaxDeg"];
+	        this.predictiveSearch = source["predictiveSearch"];
+	        this.reacquireAfterFrames = source["reacquireAfterFrames"];
+	        this.autoPickFace = source["autoPickFace"];
+	        this.pointingMode = source["pointingMode"];
+	        this.headPoseModelPath = source["headPoseModelPath"];
+	        this.poseYawMin = source["poseYawMin"];
+	        this.poseYawMax = source["poseYawMax"];
+	        this.posePitchMin = source["posePitchMin"];
+	        this.posePitchMax = source["posePitchMax"];
 	        this.gainMultiplier = source["gainMultiplier"];
 	        this.smoothing = source["smoothing"];
 	        this.dwellEnabled = source["dwellEnabled"];
 	        this.dwellTimeMs = source["dwellTimeMs"];
 	        this.autoStart = source["autoStart"];
 	        this.rightClickEnabled = source["rightClickEnabled"];
+	        this.cameraDeviceId = source["cameraDeviceId"];
+	        this.cameraDevicePath = source["cameraDevicePath"];
+	        this.cameraUrl = source["cameraUrl"];
+	        this.captureWidth = source["captureWidth"];
+	        this.captureHeight = source["captureHeight"];
+	        this.captureFps = source["captureFps"];
+	        this.capturePixelFormat = source["capturePixelFormat"];
+	        this.reconnectTimeoutSec = source["reconnectTimeoutSec"];
+	        this.preprocessEqualize = source["preprocessEqualize"];
+	        this.preprocessGamma = source["preprocessGamma"];
+	        this.preprocessDenoise = source["preprocessDenoise"];
+	        this.preprocessNormalize = source["preprocessNormalize"];
+	        this.previewProcessed = source["previewProcessed"];
+	        this.cropX = source["cropX"];
+	        this.cropY = source["cropY"];
+	        this.cropWidth = source["cropWidth"];
+	        this.cropHeight = source["cropHeight"];
+	        this.cameraRotation = source["cameraRotation"];
+	        this.mirrorImage = source["mirrorImage"];
 	    }
 	}
 
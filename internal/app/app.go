@@ -3,70 +3,164 @@ package app
 import (
 	"context"
 	"errors"
+	"image"
+	"math"
+	"path/filepath"
 	"sync"
+	"time"
 
 	"open-camera-mouse/internal/camera"
 	"open-camera-mouse/internal/config"
+	"open-camera-mouse/internal/face"
 	"open-camera-mouse/internal/mouse"
+	"open-camera-mouse/internal/preprocess"
 	"open-camera-mouse/internal/preview"
+	"open-camera-mouse/internal/recorder"
 	"open-camera-mouse/internal/tracking"
+
+	"gocv.io/x/gocv"
 )
 
-const commandBufferSize = 8
+const (
+	commandBufferSize = 8
+	// sessionsDir is where recordings go, relative to the config directory.
+	sessionsDir = "sessions"
+	// autoPickFrames is how many frames SendAutoPick looks for a face
+	// before giving up, about a second at 30 fps.
+	autoPickFrames = 30
+	// qualityInterval is how often Status is re-emitted while tracking, to
+	// refresh the match quality it carries.
+	qualityInterval = 500 * time.Millisecond
+)
 
 var (
 	ErrAlreadyRunning = errors.New("app: already running")
 	ErrNotRunning     = errors.New("app: not running")
+
+	errCameraReadFailing = errors.New("app: camera stopped delivering frames, reconnecting")
+	errCameraGaveUp      = errors.New("app: camera did not come back within the reconnect timeout")
+	errFaceNotFound      = errors.New("app: no face found to pick")
 )
 
+// Status is emitted whenever tracking is lost/regained, a full-frame search
+// for the lost target starts, the camera reports a new capture mode, the
+// camera disconnects/reconnects, or a recording starts or stops — and every
+// qualityInterval while tracking, for the match quality.
 type Status struct {
 	Running bool `json:"running"`
 	Lost    bool `json:"lost"`
+	// Reacquiring is set while the target has been lost long enough that
+	// the whole frame is searched for it; it clears when tracking re-locks.
+	Reacquiring        bool         `json:"reacquiring"`
+	Camera             *camera.Mode `json:"camera,omitempty"`
+	CameraDisconnected bool         `json:"cameraDisconnected"`
+	Recording          bool         `json:"recording"`
+	// Score, Sharpness and SecondBestRatio describe the latest match; see
+	// tracking.Result.
+	Score           float64 `json:"score"`
+	Sharpness       float64 `json:"sharpness"`
+	SecondBestRatio float64 `json:"secondBestRatio"`
+}
+
+// configurableSource is a FrameSource whose capture device follows
+// config.Params and that reports what the device negotiated (the webcam, as
+// opposed to a file replay).
+type configurableSource interface {
+	camera.FrameSource
+	SetOptions(camera.Options)
+	Events() <-chan camera.Event
+	Active() (camera.ActiveDevice, bool)
 }
 
 type App struct {
-	cfg     *config.Manager
-	camera  *camera.Service
-	tracker *tracking.Tracker
-	mouse   *mouse.Mouse
+	cfg        *config.Manager
+	source     camera.FrameSource
+	preprocess *preprocess.Processor
+	tracker    tracking.Tracker
+	mouse      *mouse.Mouse
 
 	commands chan command
 
 	EmitPreview func(preview.Frame)
 	EmitStatus  func(Status)
 	EmitRunning func(bool)
+	// EmitCameraState reports camera disconnects (false) and successful
+	// reconnects (true) while a session keeps running.
+	EmitCameraState func(connected bool)
+	// EmitError reports problems the user should know about; see Error.
+	EmitError func(Error)
 
 	mu      sync.Mutex
 	params  config.Params
 	cancel  context.CancelFunc
 	done    chan struct{}
 	running bool
+	lastErr *Error
 
 	// runtime state — only accessed from run goroutine
 	trackingEnabled bool
 	recentering     bool
 	lastLost        bool
+	lastReacquiring bool
+	lastMatch       tracking.Result
+	lastStatusAt    time.Time
 	pendingPick     bool
 	pendingPickX    int
 	pendingPickY    int
 	pendingRecenter bool
+	pendingAutoPick int
+	// pendingRepick re-picks lastPoint after the tracker was replaced by
+	// one of another kind, so switching kinds keeps the target.
+	pendingRepick   bool
+	lastPoint       image.Point
+	frameWidth      int
+	trackerKind     string
+	previewFiltered bool
+	autoPickFace    bool
+	crop            camera.Crop
+	rotation        int
+	mirror          bool
+	rotated         gocv.Mat
+	streamOpts      camera.Options
+	cameraMode      *camera.Mode
+	cameraLost      bool
+	restartStream   bool
 	enc             *preview.Encoder
+	rec             *recorder.Recorder
+	faces           *face.Detector
+	// absolute is set in absolute pointing mode, where the head's pose
+	// places the cursor; calibrating collects poses into calibration.
+	absolute        bool
+	calibrating     bool
+	calibration     poseRange
+	poses           *tracking.HeadPoseEstimator
+	poseModelPath   string
+	poseModelFailed bool
 }
 
-func NewApp(cfg *config.Manager) (*App, error) {
+// NewApp wires the runtime around source, which supplies the frames the
+// tracker follows — normally the webcam, or a recording when reproducing a
+// user's report.
+func NewApp(cfg *config.Manager, source camera.FrameSource) (*App, error) {
 	params, err := cfg.Load()
 	if err != nil {
 		return nil, err
 	}
+	return newApp(cfg, source, params, mouse.New(mouseParams(params))), nil
+}
 
+func newApp(cfg *config.Manager, source camera.FrameSource, params config.Params, m *mouse.Mouse) *App {
 	return &App{
-		cfg:      cfg,
-		camera:   camera.NewService(0),
-		tracker:  tracking.New(tracking.Params{TemplateSizePx: params.TemplateSizePx}),
-		mouse:    mouse.New(mouseParams(params)),
-		commands: make(chan command, commandBufferSize),
-		params:   params,
-	}, nil
+		cfg:         cfg,
+		source:      source,
+		preprocess:  preprocess.New(preprocessParams(params)),
+		tracker:     tracking.New(trackingParams(params)),
+		trackerKind: trackingParams(params).Kind,
+		mouse:       m,
+		commands:    make(chan command, commandBufferSize),
+		rotated:     gocv.NewMat(),
+		params:      params,
+	}
 }
 
 func (a *App) Start(ctx context.Context) error {
@@ -102,7 +196,13 @@ func (a *App) Stop() error {
 }
 
 func (a *App) Close() {
+	if a.faces != nil {
+		a.faces.Close()
+	}
+	a.closePoses()
 	a.tracker.Close()
+	a.preprocess.Close()
+	a.rotated.Close()
 }
 
 func (a *App) IsRunning() bool {
@@ -117,7 +217,10 @@ func (a *App) GetParams() config.Params {
 	return a.params
 }
 
+// UpdateParams sanitizes p as config.Load would, then saves it and applies
+// it to a running session.
 func (a *App) UpdateParams(p config.Params) error {
+	p = config.Sanitize(p)
 	if err := a.cfg.Save(p); err != nil {
 		return err
 	}
@@ -131,6 +234,29 @@ func (a *App) UpdateParams(p config.Params) error {
 	return nil
 }
 
+// ListCameras probes the capture devices attached to the machine, except the
+// one a running session is streaming from, which is reported as negotiated.
+func (a *App) ListCameras() []camera.Device {
+	var active *camera.ActiveDevice
+	if src, ok := a.source.(configurableSource); ok {
+		if d, ok := src.Active(); ok {
+			active = &d
+		}
+	}
+	return camera.ListDevices(active)
+}
+
+// SelectCamera persists the capture device, replacing any network camera URL.
+// A running session restarts its stream on the new device right away;
+// otherwise it is used on the next Start.
+func (a *App) SelectCamera(deviceID int, devicePath string) error {
+	p := a.GetParams()
+	p.CameraDeviceID = deviceID
+	p.CameraDevicePath = devicePath
+	p.CameraURL = ""
+	return a.UpdateParams(p)
+}
+
 func (a *App) SendPickPoint(x, y int) error {
 	if !a.IsRunning() {
 		return ErrNotRunning
@@ -149,7 +275,9 @@ func (a *App) SendBeginRecenter() error {
 }
 
 // SendConfirmRecenter picks the current frame's center as the new tracking
-// target and resumes tracking. Must follow a SendBeginRecenter call.
+// target and resumes tracking. Must follow a SendBeginRecenter call. With
+// Params.AutoPickFace the user's face is picked instead when one is found
+// in that frame.
 func (a *App) SendConfirmRecenter() error {
 	if !a.IsRunning() {
 		return ErrNotRunning
@@ -157,50 +285,100 @@ func (a *App) SendConfirmRecenter() error {
 	return a.sendCommand(command{kind: cmdConfirmRecenter})
 }
 
+// SendAutoPick looks for the user's face over the next frames and picks the
+// bridge of the nose as the new tracking target. If no face turns up, the
+// current target is kept and a face_not_found error is reported.
+func (a *App) SendAutoPick() error {
+	if !a.IsRunning() {
+		return ErrNotRunning
+	}
+	return a.sendCommand(command{kind: cmdAutoPick})
+}
+
 func (a *App) SendResetMouse() error {
+	if !a.IsRunning() {
+		return ErrNotRunning
+	}
 	return a.sendCommand(command{kind: cmdResetMouse})
 }
 
 func (a *App) SendSetTrackingEnabled(enabled bool) error {
+	if !a.IsRunning() {
+		return ErrNotRunning
+	}
 	return a.sendCommand(command{kind: cmdSetTrackingEnabled, enabled: enabled})
 }
 
+// SendStartRecording starts writing the session — raw frames, tracking
+// results, commands and cursor output — to a new directory under the config
+// dir, and returns that directory. A recording already in progress is ended.
+func (a *App) SendStartRecording() (string, error) {
+	if !a.IsRunning() {
+		return "", ErrNotRunning
+	}
+	rec, err := recorder.Start(filepath.Join(a.cfg.Dir(), sessionsDir), a.GetParams())
+	if err != nil {
+		return "", err
+	}
+	if err := a.sendCommand(command{kind: cmdStartRecording, recorder: rec}); err != nil {
+		rec.Close()
+		return "", err
+	}
+	return rec.Dir(), nil
+}
+
+// SendStopRecording ends the current recording, if any. Stopping the session
+// ends it too.
+func (a *App) SendStopRecording() error {
+	if !a.IsRunning() {
+		return ErrNotRunning
+	}
+	return a.sendCommand(command{kind: cmdStopRecording})
+}
+
 func (a *App) sendCommand(cmd command) error {
 	select {
 	case a.commands <- cmd:
 		return nil
 	default:
-		return errors.New("app: command queue full")
+		a.reportError(ErrCodeCommandQueueFull, ErrCommandQueueFull)
+		return ErrCommandQueueFull
 	}
 }
 
 func (a *App) run(ctx context.Context) {
 	defer func() {
+		a.closeRecorders()
 		a.mu.Lock()
 		a.running = false
 		close(a.done)
 		a.mu.Unlock()
 	}()
 
-	frames, err := a.camera.Stream(ctx)
+	a.mu.Lock()
+	params := a.params
+	a.mu.Unlock()
+
+	a.streamOpts = cameraOptions(params)
+	a.restartStream = false
+	a.cameraMode = nil
+	a.cameraLost = false
+	var cameraEvents <-chan camera.Event
+	if src, ok := a.source.(configurableSource); ok {
+		cameraEvents = src.Events()
+	}
+	frames, stopStream, err := a.openStream(ctx)
+	defer func() { stopStream() }()
 	if err != nil {
+		a.reportError(cameraErrorCode(err), err)
 		if a.EmitRunning != nil {
 			a.EmitRunning(false)
 		}
 		return
 	}
+	a.clearLastError()
 
-	a.mu.Lock()
-	params := a.params
-	a.mu.Unlock()
-	a.tracker.SetParams(tracking.Params{TemplateSizePx: params.TemplateSizePx})
-	a.mouse.SetParams(mouseParams(params))
-
-	a.enc = preview.NewEncoder()
-	a.lastLost = true
-	a.trackingEnabled = true
-	a.recentering = false
-	a.mouse.Reset()
+	a.resetRunState(params)
 
 	for {
 		select {
@@ -208,8 +386,37 @@ func (a *App) run(ctx context.Context) {
 			return
 		case cmd := <-a.commands:
 			a.handleCommand(cmd)
+			if a.restartStream {
+				a.restartStream = false
+				stopStream()
+				drainFrames(frames)
+				frames, stopStream, err = a.openStream(ctx)
+				if err != nil {
+					a.reportError(cameraErrorCode(err), err)
+					if a.EmitRunning != nil {
+						a.EmitRunning(false)
+					}
+					return
+				}
+				// A device switched to while the old one was
+				// disconnected is a fresh start, not a lost camera.
+				if a.cameraLost {
+					a.cameraRecovered()
+					a.emitStatus()
+				}
+			}
+		case ev := <-cameraEvents:
+			a.handleCameraEvent(ev)
 		case frame, ok := <-frames:
 			if !ok {
+				// The source ended on its own (file finished, camera
+				// reconnect gave up) rather than through Stop.
+				if ctx.Err() == nil && a.cameraLost {
+					a.reportError(ErrCodeCameraRead, errCameraGaveUp)
+				}
+				if ctx.Err() == nil && a.EmitRunning != nil {
+					a.EmitRunning(false)
+				}
 				return
 			}
 			a.handleFrame(frame)
@@ -217,63 +424,336 @@ func (a *App) run(ctx context.Context) {
 	}
 }
 
-func (a *App) handleFrame(frame camera.Frame) {
-	defer frame.Mat.Close()
+// resetRunState applies params and clears the per-session state at the
+// start of a run (or a replay).
+func (a *App) resetRunState(params config.Params) {
+	a.preprocess.SetParams(preprocessParams(params))
+	a.previewFiltered = params.PreviewProcessed
+	a.autoPickFace = params.AutoPickFace
+	a.crop = cropParams(params)
+	a.rotation = params.CameraRotation
+	a.mirror = params.MirrorImage
+	a.setTracker(params)
+	a.mouse.SetParams(mouseParams(params))
+	a.absolute = params.PointingMode == config.PointingAbsolute
+	a.setPoseModel(params.HeadPoseModelPath)
+
+	a.enc = preview.NewEncoder(params.MirrorImage)
+	a.lastLost = true
+	a.lastReacquiring = false
+	a.lastMatch = tracking.Result{}
+	a.lastStatusAt = time.Time{}
+	a.trackingEnabled = true
+	a.recentering = false
+	a.pendingAutoPick = 0
+	a.calibrating = false
+	a.mouse.Reset()
+}
+
+// openStream starts the frame source under its own cancelable context so a
+// device switch can restart capture without ending the run. The returned
+// cancel func is always safe to call, even on error.
+func (a *App) openStream(ctx context.Context) (<-chan camera.Frame, context.CancelFunc, error) {
+	if src, ok := a.source.(configurableSource); ok {
+		src.SetOptions(a.streamOpts)
+	}
+	streamCtx, cancel := context.WithCancel(ctx)
+	frames, err := a.source.Stream(streamCtx)
+	if err != nil {
+		cancel()
+		return nil, cancel, err
+	}
+	return frames, cancel, nil
+}
+
+// drainFrames releases frames still buffered by a cancelled stream and waits
+// for its goroutine to close the channel (and with it the device).
+func drainFrames(frames <-chan camera.Frame) {
+	for f := range frames {
+		f.Release()
+	}
+}
+
+// frameOutput is what handleFrame made of a frame: the tracker result (nil
+// when tracking was paused or disabled) and the cursor update (nil while
+// recentering).
+type frameOutput struct {
+	tracking *tracking.Result
+	cursor   *mouse.Output
+}
+
+func (a *App) handleFrame(raw camera.Frame) frameOutput {
+	defer raw.Release()
+
+	// Everything downstream — tracking, pick coordinates, the preview —
+	// works in the upright, cropped frame's coordinate space.
+	frame, closeView := a.orient(raw)
+	defer closeView()
+	a.frameWidth = frame.Width
+
+	// Pick and Update must see the same preprocessed image, otherwise the
+	// template and the search region come from different domains. The
+	// marker tracker segments color, which preprocessing would discard.
+	img := frame.Mat
+	if a.preprocess.Enabled() && a.trackerKind != tracking.KindMarker {
+		img = a.preprocess.Apply(frame.Mat)
+	}
 
 	if a.pendingPick {
 		a.pendingPick = false
-		// pendingPickX/Y arrive in mirrored (display) coordinates — convert
-		// to raw-frame space to match frame.Mat, which is never flipped.
+		// pendingPickX/Y arrive in display coordinates — convert to
+		// frame space to match frame.Mat, which is never flipped.
 		displayX := clampToFrame(a.pendingPickX, frame.Width)
 		displayY := clampToFrame(a.pendingPickY, frame.Height)
-		rawX := frame.Width - 1 - displayX
-		_ = a.tracker.Pick(frame.Mat, rawX, displayY)
+		if err := a.tracker.Pick(img, a.displayX(displayX, frame.Width), displayY); err != nil {
+			a.reportError(ErrCodePickRejected, err)
+		}
 		a.mouse.Reset()
 	}
 	if a.pendingRecenter {
 		a.pendingRecenter = false
 		a.recentering = false
-		_ = a.tracker.Pick(frame.Mat, frame.Width/2, frame.Height/2)
+		target := image.Pt(frame.Width/2, frame.Height/2)
+		if a.autoPickFace {
+			if pt, ok := a.detectFace(frame.Mat); ok {
+				target = pt
+			}
+		}
+		if err := a.tracker.Pick(img, target.X, target.Y); err != nil {
+			a.reportError(ErrCodePickRejected, err)
+		}
 		a.mouse.Reset()
 	}
+	if a.pendingRepick {
+		a.pendingRepick = false
+		if err := a.tracker.Pick(img, a.lastPoint.X, a.lastPoint.Y); err != nil {
+			a.reportError(ErrCodePickRejected, err)
+		}
+	}
+	if a.pendingAutoPick > 0 {
+		a.pendingAutoPick--
+		if pt, ok := a.detectFace(frame.Mat); ok {
+			a.pendingAutoPick = 0
+			if err := a.tracker.Pick(img, pt.X, pt.Y); err != nil {
+				a.reportError(ErrCodePickRejected, err)
+			}
+			a.mouse.Reset()
+		} else if a.pendingAutoPick == 0 {
+			a.reportError(ErrCodeFaceNotFound, errFaceNotFound)
+		}
+	}
 
 	var result tracking.Result
+	tracked := false
 	switch {
 	case a.recentering:
 		result = tracking.Result{Lost: true}
 	case a.trackingEnabled:
-		result = a.tracker.Update(frame.Mat)
+		result = a.tracker.Update(img)
+		tracked = true
+		if !result.Lost {
+			a.lastPoint = image.Pt(result.X, result.Y)
+		}
+		if a.absolute || a.calibrating {
+			result.Pose = a.estimatePose(frame.Mat)
+			if a.calibrating && result.Pose != nil {
+				a.calibration.add(*result.Pose)
+			}
+		}
 	default:
 		result = tracking.Result{Lost: true}
 	}
 
+	// In absolute mode the head's pose drives the cursor, so it is lost
+	// without a face rather than without the tracked point.
+	lost := result.Lost
+	if a.absolute {
+		lost = result.Pose == nil
+	}
+
+	var cursor *mouse.Output
 	if !a.recentering {
-		a.mouse.Update(result.X, result.Y, result.Lost)
+		var out mouse.Output
+		if a.absolute {
+			var pose tracking.Pose
+			if result.Pose != nil {
+				pose = *result.Pose
+			}
+			out = a.mouse.UpdateAbsolute(pose.Yaw, pose.Pitch, lost, frame.CapturedAt)
+		} else {
+			// A predicted point keeps the cursor gliding through a brief loss.
+			x, y := result.Position()
+			out = a.mouse.Update(x, y, lost && !result.Predicted, frame.CapturedAt)
+		}
+		cursor = &out
+	}
+
+	out := frameOutput{cursor: cursor}
+	if tracked {
+		out.tracking = &result
+	}
+	if a.rec != nil {
+		if err := a.rec.Frame(raw, out.tracking, out.cursor); err != nil {
+			a.failRecording(err)
+		}
 	}
 
-	if !a.recentering && result.Lost != a.lastLost {
-		a.lastLost = result.Lost
-		if a.EmitStatus != nil {
-			a.EmitStatus(Status{Running: true, Lost: result.Lost})
+	if !a.recentering {
+		a.lastMatch = result
+		since := frame.CapturedAt.Sub(a.lastStatusAt)
+		if lost != a.lastLost || result.Reacquiring != a.lastReacquiring ||
+			(tracked && (since < 0 || since >= qualityInterval)) {
+			a.lastLost = lost
+			a.lastReacquiring = result.Reacquiring
+			a.lastStatusAt = frame.CapturedAt
+			a.emitStatus()
 		}
 	}
 
 	var overlay *preview.TrackingOverlay
 	if !a.recentering && a.tracker.HasTemplate() {
 		overlay = &preview.TrackingOverlay{
-			X:              frame.Width - 1 - result.X,
-			Y:              result.Y,
-			TemplateSizePx: a.params.TemplateSizePx,
-			Lost:           result.Lost,
+			X:               a.displayX(result.X, frame.Width),
+			Y:               result.Y,
+			TemplateSizePx:  scaledSize(a.params.TemplateSizePx, result.Scale),
+			Lost:            result.Lost,
+			Score:           result.Score,
+			Sharpness:       result.Sharpness,
+			SecondBestRatio: result.SecondBestRatio,
+		}
+	}
+
+	if a.EmitPreview != nil {
+		view := frame
+		if a.previewFiltered {
+			view.Mat = img
+		}
+		if f := a.enc.Encode(view, overlay); f != nil {
+			a.EmitPreview(*f)
 		}
 	}
+	return out
+}
 
-	if f := a.enc.Encode(frame, overlay); f != nil && a.EmitPreview != nil {
-		a.EmitPreview(*f)
+// setTracker applies p's tracking params, replacing the tracker when p asks
+// for a different kind. The new one re-picks the point the old one was
+// last following on the next frame.
+func (a *App) setTracker(p config.Params) {
+	params := trackingParams(p)
+	if params.Kind == a.trackerKind {
+		a.tracker.SetParams(params)
+		return
+	}
+	a.pendingRepick = a.tracker.HasTemplate()
+	a.tracker.Close()
+	a.tracker = tracking.New(params)
+	a.trackerKind = params.Kind
+	a.mouse.Reset()
+}
+
+// detectFace returns the point to track on the user's face in frame, in
+// frame coordinates. Detection runs on the unprocessed frame, which is what
+// the cascade was trained on. The detector is loaded on first use.
+func (a *App) detectFace(frame gocv.Mat) (image.Point, bool) {
+	if a.faces == nil {
+		d, err := face.NewDetector()
+		if err != nil {
+			a.pendingAutoPick = 0
+			a.reportError(ErrCodeFaceDetector, err)
+			return image.Point{}, false
+		}
+		a.faces = d
+	}
+	r, ok := a.faces.Detect(frame)
+	if !ok {
+		return image.Point{}, false
+	}
+	return face.Target(r), true
+}
+
+// orient turns a captured frame into the upright, cropped view every later
+// stage works in: rotated for the camera's mounting, then cropped to the
+// region of interest (a view, no copy). The returned func frees the view and
+// must be called before raw is released.
+func (a *App) orient(raw camera.Frame) (camera.Frame, func()) {
+	frame := camera.Rotate(raw, a.rotation, &a.rotated)
+	rect := a.crop.Rect(frame.Width, frame.Height, a.mirror)
+	if rect == image.Rect(0, 0, frame.Width, frame.Height) {
+		return frame, func() {}
+	}
+	frame.Mat = frame.Mat.Region(rect)
+	frame.Width = rect.Dx()
+	frame.Height = rect.Dy()
+	return frame, func() { frame.Mat.Close() }
+}
+
+// displayX converts between frame and display (preview) x coordinates; the
+// mapping is its own inverse. The preview mirrors the frame unless the camera
+// already delivers a mirrored image.
+func (a *App) displayX(x, width int) int {
+	if !a.mirror {
+		return x
+	}
+	return width - 1 - x
+}
+
+func (a *App) handleCameraEvent(ev camera.Event) {
+	a.recordCameraEvent(ev.Kind)
+	switch ev.Kind {
+	case camera.EventOpened:
+		mode := ev.Mode
+		a.cameraMode = &mode
+		if a.cameraLost {
+			a.cameraRecovered()
+		}
+		a.emitStatus()
+	case camera.EventDisconnected:
+		// Keep the tracker template so tracking resumes where it left off;
+		// only the cursor state is dropped so nothing jumps on reconnect.
+		a.cameraLost = true
+		a.lastLost = true
+		a.mouse.Reset()
+		a.emitStatus()
+		a.reportError(ErrCodeCameraRead, errCameraReadFailing)
+		if a.EmitCameraState != nil {
+			a.EmitCameraState(false)
+		}
+	case camera.EventReconnected:
+		mode := ev.Mode
+		a.cameraMode = &mode
+		a.cameraRecovered()
+		a.emitStatus()
+	}
+}
+
+// cameraRecovered clears a disconnect once frames flow again, whether the
+// same device reconnected or the stream was restarted on another one.
+func (a *App) cameraRecovered() {
+	a.cameraLost = false
+	a.mouse.Reset()
+	if a.EmitCameraState != nil {
+		a.EmitCameraState(true)
+	}
+}
+
+func (a *App) emitStatus() {
+	if a.EmitStatus != nil {
+		a.EmitStatus(Status{
+			Running:            true,
+			Lost:               a.lastLost,
+			Reacquiring:        a.lastReacquiring,
+			Score:              a.lastMatch.Score,
+			Sharpness:          a.lastMatch.Sharpness,
+			SecondBestRatio:    a.lastMatch.SecondBestRatio,
+			Camera:             a.cameraMode,
+			CameraDisconnected: a.cameraLost,
+			Recording:          a.rec != nil,
+		})
 	}
 }
 
 func (a *App) handleCommand(cmd command) {
+	a.recordCommand(cmd)
 	switch cmd.kind {
 	case cmdPickPoint:
 		a.pendingPick = true
@@ -284,8 +764,31 @@ func (a *App) handleCommand(cmd command) {
 	case cmdConfirmRecenter:
 		a.pendingRecenter = true
 	case cmdSetParams:
-		a.tracker.SetParams(tracking.Params{TemplateSizePx: cmd.params.TemplateSizePx})
+		a.preprocess.SetParams(preprocessParams(cmd.params))
+		a.previewFiltered = cmd.params.PreviewProcessed
+		a.autoPickFace = cmd.params.AutoPickFace
+		if crop := cropParams(cmd.params); crop != a.crop || cmd.params.CameraRotation != a.rotation ||
+			cmd.params.MirrorImage != a.mirror {
+			// The tracking point's coordinates are relative to the old
+			// geometry; drop cursor state so the shift isn't read as motion.
+			a.crop = crop
+			a.rotation = cmd.params.CameraRotation
+			a.mirror = cmd.params.MirrorImage
+			a.enc.SetMirror(a.mirror)
+			a.mouse.Reset()
+		}
+		a.setTracker(cmd.params)
 		a.mouse.SetParams(mouseParams(cmd.params))
+		if absolute := cmd.params.PointingMode == config.PointingAbsolute; absolute != a.absolute {
+			a.absolute = absolute
+			a.mouse.Reset()
+		}
+		a.setPoseModel(cmd.params.HeadPoseModelPath)
+		if opts := cameraOptions(cmd.params); opts != a.streamOpts {
+			a.streamOpts = opts
+			_, configurable := a.source.(configurableSource)
+			a.restartStream = configurable
+		}
 	case cmdSetTrackingEnabled:
 		a.trackingEnabled = cmd.enabled
 		if !cmd.enabled {
@@ -293,9 +796,122 @@ func (a *App) handleCommand(cmd command) {
 		}
 	case cmdResetMouse:
 		a.mouse.Reset()
+	case cmdAutoPick:
+		a.pendingAutoPick = autoPickFrames
+	case cmdStartRecording:
+		if a.rec != nil {
+			_ = a.rec.Close()
+		}
+		a.rec = cmd.recorder
+		a.recordState()
+		a.emitStatus()
+	case cmdStopRecording:
+		a.stopRecording()
+	case cmdBeginPoseCalibration:
+		a.calibrating = true
+		a.calibration = poseRange{}
+	case cmdFinishPoseCalibration:
+		cmd.calibrated <- a.calibration
+		a.calibrating = false
+		a.calibration = poseRange{}
+	}
+}
+
+func (a *App) recordCommand(cmd command) {
+	if a.rec == nil || cmd.kind >= cmdStartRecording {
+		return
+	}
+	var params *config.Params
+	if cmd.kind == cmdSetParams {
+		params = &cmd.params
+	}
+	if err := a.rec.Command(cmd.kind.String(), cmd.x, cmd.y, cmd.enabled, params); err != nil {
+		a.failRecording(err)
+	}
+}
+
+// recordState writes the state a recording started mid-run begins in as the
+// commands that would have produced it, so a replay starts out following the
+// same point: the pick (in display coordinates, like the user's), whether
+// tracking is on, and a recenter or face search under way. A re-pick pending
+// after a tracker switch is recorded as a pick of the same point.
+//
+// The tracker's own state — an adapted template, the matched scale and
+// angle, the predictor's velocity — is not recorded: the replay starts from
+// a fresh pick, so it only reproduces the live run exactly for recordings
+// started before the first pick.
+func (a *App) recordState() {
+	switch {
+	case a.pendingPick:
+		a.recordCommand(command{kind: cmdPickPoint, x: a.pendingPickX, y: a.pendingPickY})
+	case a.tracker.HasTemplate() || a.pendingRepick:
+		a.recordCommand(command{kind: cmdPickPoint, x: a.displayX(a.lastPoint.X, a.frameWidth), y: a.lastPoint.Y})
+	}
+	a.recordCommand(command{kind: cmdSetTrackingEnabled, enabled: a.trackingEnabled})
+	if a.recentering {
+		a.recordCommand(command{kind: cmdBeginRecenter})
+	}
+	if a.pendingRecenter {
+		a.recordCommand(command{kind: cmdConfirmRecenter})
+	}
+	if a.pendingAutoPick > 0 {
+		a.recordCommand(command{kind: cmdAutoPick})
 	}
 }
 
+func (a *App) recordCameraEvent(kind camera.EventKind) {
+	if a.rec == nil {
+		return
+	}
+	if err := a.rec.Camera(kind.String()); err != nil {
+		a.failRecording(err)
+	}
+}
+
+// failRecording ends the recording after a write error (e.g. disk full)
+// rather than stopping tracking.
+func (a *App) failRecording(err error) {
+	a.stopRecording()
+	a.reportError(ErrCodeRecording, err)
+}
+
+func (a *App) stopRecording() {
+	if a.rec == nil {
+		return
+	}
+	_ = a.rec.Close()
+	a.rec = nil
+	a.emitStatus()
+}
+
+// closeRecorders finishes the recording when run exits, including one whose
+// start command was still queued.
+func (a *App) closeRecorders() {
+	if a.rec != nil {
+		_ = a.rec.Close()
+		a.rec = nil
+	}
+	for {
+		select {
+		case cmd := <-a.commands:
+			if cmd.recorder != nil {
+				_ = cmd.recorder.Close()
+			}
+		default:
+			return
+		}
+	}
+}
+
+// scaledSize is the template's size at the tracker's current scale, so the
+// overlay box follows the user leaning in or out.
+func scaledSize(size int, scale float64) int {
+	if scale <= 0 {
+		return size
+	}
+	return int(math.Round(float64(size) * scale))
+}
+
 // clampToFrame keeps a coordinate within the valid pixel index range
 // [0, dim-1] for a frame of the given dimension.
 func clampToFrame(v, dim int) int {
@@ -308,6 +924,49 @@ func clampToFrame(v, dim int) int {
 	return v
 }
 
+func cameraOptions(p config.Params) camera.Options {
+	return camera.Options{
+		URL:              p.CameraURL,
+		DeviceID:         p.CameraDeviceID,
+		DevicePath:       p.CameraDevicePath,
+		Width:            p.CaptureWidth,
+		Height:           p.CaptureHeight,
+		FPS:              p.CaptureFPS,
+		PixelFormat:      p.CapturePixelFormat,
+		ReconnectTimeout: time.Duration(p.ReconnectTimeoutSec) * time.Second,
+	}
+}
+
+func cropParams(p config.Params) camera.Crop {
+	return camera.Crop{X: p.CropX, Y: p.CropY, Width: p.CropWidth, Height: p.CropHeight}
+}
+
+func trackingParams(p config.Params) tracking.Params {
+	return tracking.Params{
+		Kind:                 p.TrackerKind,
+		TemplateSizePx:       p.TemplateSizePx,
+		ScoreThreshold:       p.ScoreThreshold,
+		SearchMargin:         p.SearchMargin,
+		AdaptiveTemplate:     p.AdaptiveTemplate,
+		AdaptiveRate:         p.AdaptiveRate,
+		AdaptiveMinScore:     p.AdaptiveMinScore,
+		ScaleMin:             p.ScaleMin,
+		ScaleMax:             p.ScaleMax,
+		RotationMaxDeg:       float64(p.RotationMaxDeg),
+		Predictive:           p.PredictiveSearch,
+		ReacquireAfterFrames: p.ReacquireAfterFrames,
+	}
+}
+
+func preprocessParams(p config.Params) preprocess.Params {
+	return preprocess.Params{
+		Equalize:  p.PreprocessEqualize,
+		Gamma:     p.PreprocessGamma,
+		Denoise:   p.PreprocessDenoise,
+		Normalize: p.PreprocessNormalize,
+	}
+}
+
 func mouseParams(p config.Params) mouse.Params {
 	return mouse.Params{
 		GainMultiplier:    p.GainMultiplier,
@@ -315,5 +974,10 @@ func mouseParams(p config.Params) mouse.Params {
 		DwellEnabled:      p.DwellEnabled,
 		DwellTimeMs:       p.DwellTimeMs,
 		RightClickEnabled: p.RightClickEnabled,
+		TrueView:          p.MirrorImage,
+		YawMin:            p.PoseYawMin,
+		YawMax:            p.PoseYawMax,
+		PitchMin:          p.PosePitchMin,
+		PitchMax:          p.PosePitchMax,
 	}
 }
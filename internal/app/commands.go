@@ -1,6 +1,9 @@
 package app
 
-import "open-camera-mouse/internal/config"
+import (
+	"open-camera-mouse/internal/config"
+	"open-camera-mouse/internal/recorder"
+)
 
 type commandKind int
 
@@ -11,11 +14,50 @@ const (
 	cmdSetParams
 	cmdSetTrackingEnabled
 	cmdResetMouse
+	cmdAutoPick
+	cmdStartRecording
+	cmdStopRecording
+	// Pose calibration is not recorded: its outcome reaches the session
+	// as the setParams that saves the new range.
+	cmdBeginPoseCalibration
+	cmdFinishPoseCalibration
 )
 
+// String names the command in session recordings.
+func (k commandKind) String() string {
+	switch k {
+	case cmdPickPoint:
+		return "pickPoint"
+	case cmdBeginRecenter:
+		return "beginRecenter"
+	case cmdConfirmRecenter:
+		return "confirmRecenter"
+	case cmdSetParams:
+		return "setParams"
+	case cmdSetTrackingEnabled:
+		return "setTrackingEnabled"
+	case cmdResetMouse:
+		return "resetMouse"
+	case cmdAutoPick:
+		return "autoPick"
+	case cmdStartRecording:
+		return "startRecording"
+	case cmdStopRecording:
+		return "stopRecording"
+	case cmdBeginPoseCalibration:
+		return "beginPoseCalibration"
+	case cmdFinishPoseCalibration:
+		return "finishPoseCalibration"
+	}
+	return "unknown"
+}
+
 type command struct {
-	kind    commandKind
-	x, y    int
-	params  config.Params
-	enabled bool
+	kind     commandKind
+	x, y     int
+	params   config.Params
+	enabled  bool
+	recorder *recorder.Recorder
+	// calibrated receives the poses seen since cmdBeginPoseCalibration.
+	calibrated chan<- poseRange
 }
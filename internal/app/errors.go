@@ -0,0 +1,81 @@
+package app
+
+import (
+	"errors"
+	"time"
+
+	"open-camera-mouse/internal/camera"
+)
+
+// ErrorCode classifies an Error for the frontend, which picks the message to
+// show by code.
+type ErrorCode string
+
+const (
+	ErrCodeCameraOpen       ErrorCode = "camera_open_failed"
+	ErrCodeCameraBusy       ErrorCode = "camera_busy"
+	ErrCodeCameraRead       ErrorCode = "camera_read_failed"
+	ErrCodePickRejected     ErrorCode = "pick_rejected"
+	ErrCodeCommandQueueFull ErrorCode = "command_queue_full"
+	ErrCodeRecording        ErrorCode = "recording_failed"
+	ErrCodeFaceNotFound     ErrorCode = "face_not_found"
+	ErrCodeFaceDetector     ErrorCode = "face_detector_failed"
+	ErrCodeHeadPose         ErrorCode = "head_pose_unavailable"
+	ErrCodeCalibration      ErrorCode = "pose_calibration_failed"
+)
+
+var ErrCommandQueueFull = errors.New("app: command queue full")
+
+// Error is a problem reported while the app runs. It is emitted through
+// EmitError as it happens and kept for LastError; Message is the underlying
+// error's text, for logs and details.
+type Error struct {
+	Code    ErrorCode `json:"code"`
+	Message string    `json:"message"`
+	At      time.Time `json:"at"`
+}
+
+func (e *Error) Error() string {
+	return string(e.Code) + ": " + e.Message
+}
+
+// LastError returns the most recent error reported since the last run opened
+// its camera, or nil. It lets a frontend that attached late (e.g. after an
+// autostart failure) catch up without being shown errors a later successful
+// start has made stale.
+func (a *App) LastError() *Error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.lastErr == nil {
+		return nil
+	}
+	e := *a.lastErr
+	return &e
+}
+
+// reportError records err under code and emits it. Safe to call from any
+// goroutine.
+func (a *App) reportError(code ErrorCode, err error) {
+	e := Error{Code: code, Message: err.Error(), At: time.Now()}
+	a.mu.Lock()
+	a.lastErr = &e
+	emit := a.EmitError
+	a.mu.Unlock()
+	if emit != nil {
+		emit(e)
+	}
+}
+
+func (a *App) clearLastError() {
+	a.mu.Lock()
+	a.lastErr = nil
+	a.mu.Unlock()
+}
+
+// cameraErrorCode classifies an error from opening the frame source.
+func cameraErrorCode(err error) ErrorCode {
+	if errors.Is(err, camera.ErrDeviceBusy) {
+		return ErrCodeCameraBusy
+	}
+	return ErrCodeCameraOpen
+}
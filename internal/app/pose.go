@@ -0,0 +1,150 @@
+package app
+
+import (
+	"errors"
+	"sort"
+
+	"open-camera-mouse/internal/config"
+	"open-camera-mouse/internal/tracking"
+
+	"gocv.io/x/gocv"
+)
+
+// calibrationTrim is the share of calibration poses dropped at each end of
+// the yaw and pitch ranges, so a few misfitted landmarks don't stretch them.
+const calibrationTrim = 0.02
+
+var errCalibrationRange = errors.New("app: the head turned too little while calibrating")
+
+// SendBeginPoseCalibration starts collecting head poses for
+// SendFinishPoseCalibration; the user turns their head to point at each
+// edge of the screen in between. The cursor keeps following the old range
+// meanwhile.
+func (a *App) SendBeginPoseCalibration() error {
+	if !a.IsRunning() {
+		return ErrNotRunning
+	}
+	return a.sendCommand(command{kind: cmdBeginPoseCalibration})
+}
+
+// SendFinishPoseCalibration ends the calibration started by
+// SendBeginPoseCalibration and saves the range of poses seen as the yaw and
+// pitch ranges absolute pointing maps onto the screen. It returns the saved
+// params, or reports pose_calibration_failed and keeps the old range if the
+// head turned less than config.MinPoseSpanDeg either way.
+func (a *App) SendFinishPoseCalibration() (config.Params, error) {
+	if !a.IsRunning() {
+		return config.Params{}, ErrNotRunning
+	}
+	reply := make(chan poseRange, 1)
+	if err := a.sendCommand(command{kind: cmdFinishPoseCalibration, calibrated: reply}); err != nil {
+		return config.Params{}, err
+	}
+	a.mu.Lock()
+	done := a.done
+	a.mu.Unlock()
+
+	var r poseRange
+	select {
+	case r = <-reply:
+	case <-done:
+		return config.Params{}, ErrNotRunning
+	}
+
+	p := a.GetParams()
+	if err := r.apply(&p); err != nil {
+		a.reportError(ErrCodeCalibration, err)
+		return config.Params{}, err
+	}
+	if err := a.UpdateParams(p); err != nil {
+		return config.Params{}, err
+	}
+	return p, nil
+}
+
+// HeadPoseAvailable reports whether absolute pointing has a face model to
+// load: the file at modelPath, or the built-in model if modelPath is empty.
+func (a *App) HeadPoseAvailable(modelPath string) bool {
+	return tracking.HeadPoseAvailable(modelPath)
+}
+
+// estimatePose returns the head's pose in frame, or nil when no face was
+// found. Like face detection it runs on the unprocessed frame. The
+// estimator is loaded on first use, from Params.HeadPoseModelPath or the
+// built-in model; a model that fails to load is reported once and not
+// retried until the path changes.
+func (a *App) estimatePose(frame gocv.Mat) *tracking.Pose {
+	if a.poses == nil {
+		if a.poseModelFailed {
+			return nil
+		}
+		e, err := tracking.NewHeadPoseEstimator(a.poseModelPath)
+		if err != nil {
+			a.poseModelFailed = true
+			a.reportError(ErrCodeHeadPose, err)
+			return nil
+		}
+		a.poses = e
+	}
+	pose, ok := a.poses.Estimate(frame)
+	if !ok {
+		return nil
+	}
+	return &pose
+}
+
+// setPoseModel switches the head pose estimator to the model at path,
+// loading it on the next estimatePose.
+func (a *App) setPoseModel(path string) {
+	if path == a.poseModelPath {
+		return
+	}
+	a.closePoses()
+	a.poseModelPath = path
+	a.poseModelFailed = false
+}
+
+func (a *App) closePoses() {
+	if a.poses != nil {
+		a.poses.Close()
+		a.poses = nil
+	}
+}
+
+// poseRange collects the head poses seen while calibrating.
+type poseRange struct {
+	yaws, pitches []float64
+}
+
+func (r *poseRange) add(p tracking.Pose) {
+	r.yaws = append(r.yaws, p.Yaw)
+	r.pitches = append(r.pitches, p.Pitch)
+}
+
+// apply stores the trimmed yaw and pitch ranges in p.
+func (r poseRange) apply(p *config.Params) error {
+	yawMin, yawMax, ok := trimmedRange(r.yaws)
+	if !ok {
+		return errCalibrationRange
+	}
+	pitchMin, pitchMax, ok := trimmedRange(r.pitches)
+	if !ok {
+		return errCalibrationRange
+	}
+	p.PoseYawMin, p.PoseYawMax = yawMin, yawMax
+	p.PosePitchMin, p.PosePitchMax = pitchMin, pitchMax
+	return nil
+}
+
+// trimmedRange is the span of v without its calibrationTrim extremes; ok is
+// false if that is narrower than config.MinPoseSpanDeg.
+func trimmedRange(v []float64) (lo, hi float64, ok bool) {
+	if len(v) == 0 {
+		return 0, 0, false
+	}
+	s := append([]float64(nil), v...)
+	sort.Float64s(s)
+	cut := int(float64(len(s)) * calibrationTrim)
+	lo, hi = s[cut], s[len(s)-1-cut]
+	return lo, hi, hi-lo >= config.MinPoseSpanDeg
+}
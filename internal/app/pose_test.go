@@ -0,0 +1,36 @@
+package app
+
+import "testing"
+
+func TestTrimmedRange(t *testing.T) {
+	ramp := make([]float64, 100)
+	for i := range ramp {
+		ramp[i] = float64(i)
+	}
+	// Two stray estimates far outside an otherwise ±10° sweep.
+	outliers := []float64{-80, 80}
+	for i := 0; i < 98; i++ {
+		outliers = append(outliers, float64(20*(i%2)-10))
+	}
+
+	tests := []struct {
+		name   string
+		v      []float64
+		lo, hi float64
+		ok     bool
+	}{
+		{"no samples", nil, 0, 0, false},
+		{"too few to trim", []float64{3, -12, 7, 0, 15}, -12, 15, true},
+		{"trims both ends", ramp, 2, 97, true},
+		{"drops outliers", outliers, -10, 10, true},
+		{"too narrow", []float64{1, 2, 3, 4}, 1, 4, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lo, hi, ok := trimmedRange(tt.v)
+			if ok != tt.ok || (len(tt.v) > 0 && (lo != tt.lo || hi != tt.hi)) {
+				t.Errorf("trimmedRange = %v, %v, %v, want %v, %v, %v", lo, hi, ok, tt.lo, tt.hi, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"open-camera-mouse/internal/camera"
+	"open-camera-mouse/internal/mouse"
+	"open-camera-mouse/internal/recorder"
+	"open-camera-mouse/internal/tracking"
+
+	"gocv.io/x/gocv"
+)
+
+// ReplayFrame reports what one replayed frame produced.
+type ReplayFrame struct {
+	Index    int
+	T        time.Duration
+	Tracking *tracking.Result
+	Cursor   *mouse.Output
+}
+
+// Replay feeds a session recorded by SendStartRecording through the same
+// handleCommand/handleFrame path run uses, driving cursor instead of the real
+// one. Records are applied strictly in order and frame times come from the
+// recording rather than the clock, so the same session always produces the
+// same output; step is called once per frame.
+func Replay(dir string, cursor mouse.Backend, step func(ReplayFrame)) error {
+	records, err := recorder.ReadRecords(dir)
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 || records[0].Type != recorder.TypeSession || records[0].Params == nil {
+		return errors.New("app: replay: missing session header")
+	}
+	if v := records[0].Version; v != recorder.Version {
+		return fmt.Errorf("app: replay: unsupported session version %d", v)
+	}
+
+	params := *records[0].Params
+	a := newApp(nil, nil, params, mouse.NewWithBackend(mouseParams(params), cursor))
+	defer a.Close()
+	a.resetRunState(params)
+
+	frames := &segmentReader{dir: dir}
+	defer frames.close()
+
+	// Frame times only matter relative to each other; anchor them anywhere.
+	epoch := time.Unix(0, 0)
+	for _, rec := range records[1:] {
+		switch rec.Type {
+		case recorder.TypeCommand:
+			cmd, ok := replayCommand(rec)
+			if !ok {
+				return fmt.Errorf("app: replay: unknown command %q", rec.Command)
+			}
+			if cmd.kind == cmdSetParams {
+				a.params = cmd.params
+			}
+			a.handleCommand(cmd)
+		case recorder.TypeCamera:
+			if kind, ok := replayCameraEvent(rec.Event); ok {
+				a.handleCameraEvent(camera.Event{Kind: kind})
+			}
+		case recorder.TypeFrame:
+			mat, err := frames.read(rec.Segment)
+			if err != nil {
+				return fmt.Errorf("app: replay: frame %d: %w", rec.Frame, err)
+			}
+			out := a.handleFrame(camera.Frame{
+				Mat:        mat,
+				Width:      mat.Cols(),
+				Height:     mat.Rows(),
+				CapturedAt: epoch.Add(rec.T),
+				Seq:        rec.Seq,
+				Dropped:    rec.Dropped,
+			})
+			if step != nil {
+				step(ReplayFrame{Index: rec.Frame, T: rec.T, Tracking: out.tracking, Cursor: out.cursor})
+			}
+		}
+	}
+	return nil
+}
+
+// replayCommand rebuilds a recorded command. Recording commands are never
+// recorded, so they can't come back here either.
+func replayCommand(rec recorder.Record) (command, bool) {
+	for kind := cmdPickPoint; kind < cmdStartRecording; kind++ {
+		if kind.String() != rec.Command {
+			continue
+		}
+		cmd := command{kind: kind, x: rec.X, y: rec.Y, enabled: rec.Enabled}
+		if rec.Params != nil {
+			cmd.params = *rec.Params
+		}
+		return cmd, true
+	}
+	return command{}, false
+}
+
+func replayCameraEvent(name string) (camera.EventKind, bool) {
+	for _, kind := range []camera.EventKind{camera.EventOpened, camera.EventDisconnected, camera.EventReconnected} {
+		if kind.String() == name {
+			return kind, true
+		}
+	}
+	return 0, false
+}
+
+// segmentReader reads a session's frames in order across its video segments.
+type segmentReader struct {
+	dir     string
+	segment string
+	vcap    *gocv.VideoCapture
+}
+
+// read returns the next frame of segment as a new Mat owned by the caller.
+func (r *segmentReader) read(segment string) (gocv.Mat, error) {
+	if r.vcap == nil || segment != r.segment {
+		r.close()
+		vcap, err := gocv.VideoCaptureFile(filepath.Join(r.dir, segment))
+		if err != nil {
+			vcap.Close()
+			return gocv.Mat{}, err
+		}
+		r.vcap = vcap
+		r.segment = segment
+	}
+	mat := gocv.NewMat()
+	if !r.vcap.Read(&mat) || mat.Empty() {
+		mat.Close()
+		return gocv.Mat{}, fmt.Errorf("%s ended early", segment)
+	}
+	return mat, nil
+}
+
+func (r *segmentReader) close() {
+	if r.vcap != nil {
+		r.vcap.Close()
+		r.vcap = nil
+	}
+}
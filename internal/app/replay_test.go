@@ -0,0 +1,108 @@
+package app
+
+import (
+	"image"
+	"math"
+	"testing"
+	"time"
+
+	"open-camera-mouse/internal/camera"
+	"open-camera-mouse/internal/config"
+	"open-camera-mouse/internal/mouse"
+	"open-camera-mouse/internal/recorder"
+
+	"gocv.io/x/gocv"
+)
+
+// replayTolerancePx allows for the MJPEG compression of recorded frames,
+// which the live run didn't see.
+const replayTolerancePx = 3
+
+// midRunPath keeps the target visible throughout, so every replayed frame
+// has a position to check against the ground truth. The recording starts
+// during the hold: the replay re-picks the recorded point on its first
+// frame, which only lands on the same spot of the target if it stood still.
+var midRunPath = []camera.PathSegment{
+	{Kind: camera.SegmentLine, Frames: 30, To: image.Pt(420, 260)},
+	{Kind: camera.SegmentHold, Frames: 10},
+	{Kind: camera.SegmentCircle, Frames: 40, Center: image.Pt(320, 240), Turns: 0.5},
+	{Kind: camera.SegmentLine, Frames: 30, To: image.Pt(320, 240)},
+}
+
+// TestReplayMidRunRecording records a synthetic session started after the
+// point was picked and tracking was under way, then replays it: the replay
+// has to follow the target from its first frame, with the synthetic source's
+// ground truth as the golden trajectory.
+func TestReplayMidRunRecording(t *testing.T) {
+	src, err := camera.NewSyntheticSource(camera.SyntheticOptions{
+		Start: image.Pt(320, 240),
+		Path:  midRunPath,
+		Seed:  1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+
+	// Default params mirror the preview, so the recorded pick has to be
+	// converted back to display coordinates to replay at the same point.
+	params := config.DefaultParams()
+	a := newApp(nil, nil, params, mouse.NewWithBackend(mouseParams(params), mouse.NewVirtualCursor(1920, 1080)))
+	defer a.Close()
+	a.resetRunState(params)
+
+	const recordFrom = 35
+	epoch := time.Unix(0, 0)
+	interval := time.Second / 30
+	var sessionDir string
+	for i := 0; i < src.Frames(); i++ {
+		mat := gocv.NewMat()
+		truth := src.Render(i, &mat)
+		frame := camera.Frame{
+			Mat:        mat,
+			Width:      mat.Cols(),
+			Height:     mat.Rows(),
+			CapturedAt: epoch.Add(time.Duration(i) * interval),
+			Seq:        uint64(i + 1),
+		}
+		switch i {
+		case 0:
+			a.handleCommand(command{kind: cmdPickPoint, x: a.displayX(truth.X, frame.Width), y: truth.Y})
+		case recordFrom:
+			rec, err := recorder.Start(t.TempDir(), params)
+			if err != nil {
+				frame.Release()
+				t.Fatal(err)
+			}
+			sessionDir = rec.Dir()
+			a.handleCommand(command{kind: cmdStartRecording, recorder: rec})
+		}
+		a.handleFrame(frame)
+	}
+	a.stopRecording()
+
+	frames, moved := 0, 0
+	err = Replay(sessionDir, mouse.NewVirtualCursor(1920, 1080), func(f ReplayFrame) {
+		frames++
+		want := src.Truth(uint64(recordFrom + f.Index + 1))
+		if f.Tracking == nil || f.Tracking.Lost {
+			t.Errorf("frame %d: target lost, want (%d, %d)", f.Index, want.X, want.Y)
+			return
+		}
+		if d := math.Hypot(float64(f.Tracking.X-want.X), float64(f.Tracking.Y-want.Y)); d > replayTolerancePx {
+			t.Errorf("frame %d: tracked (%d, %d), want (%d, %d)", f.Index, f.Tracking.X, f.Tracking.Y, want.X, want.Y)
+		}
+		if f.Cursor != nil && f.Cursor.Moved {
+			moved++
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := src.Frames() - recordFrom; frames != want {
+		t.Errorf("replayed %d frames, want %d", frames, want)
+	}
+	if moved == 0 {
+		t.Error("replayed cursor never moved")
+	}
+}
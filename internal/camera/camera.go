@@ -2,41 +2,226 @@ package camera
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sync"
 	"time"
 
 	"gocv.io/x/gocv"
 )
 
-const maxReadFailures = 30
+const (
+	maxReadFailures = 30
+	eventBufferSize = 4
+)
+
+var (
+	// ErrOpenFailed is returned (wrapped) when a device or stream can't be
+	// opened at all: wrong index, unplugged, unreachable URL.
+	ErrOpenFailed = errors.New("camera: cannot open device")
+	// ErrDeviceBusy is returned (wrapped) when the device is there but
+	// won't deliver frames, typically because another application has it.
+	ErrDeviceBusy = errors.New("camera: device busy")
+)
 
 type Frame struct {
 	Mat    gocv.Mat
 	Width  int
 	Height int
+	// CapturedAt is taken as soon as the read returns and carries Go's
+	// monotonic clock reading, so differences between frames are real
+	// elapsed time regardless of wall-clock adjustments.
+	CapturedAt time.Time
+	// Seq numbers every frame read since the stream started, dropped ones
+	// included, so a gap between consecutive Seqs is the number skipped.
+	Seq uint64
+	// Dropped counts frames discarded since the stream started because a
+	// newer one arrived before the consumer picked them up.
+	Dropped uint64
+
+	pool *matPool
+}
+
+// Release hands the frame's Mat back to its source for reuse; the frame must
+// not be used afterwards. Every received frame must be released exactly once.
+func (f Frame) Release() {
+	if f.pool != nil {
+		f.pool.put(f.Mat)
+		return
+	}
+	f.Mat.Close()
+}
+
+// FrameSource produces frames for app.App. The returned channel is closed
+// once ctx is cancelled or the source has no more frames to give.
+type FrameSource interface {
+	Stream(ctx context.Context) (<-chan Frame, error)
+}
+
+// Options selects the capture device and the mode requested from it.
+// URL, when set, opens an MJPEG-over-HTTP or RTSP stream instead of a local
+// device; the mode fields don't apply to it. Otherwise DevicePath, when set,
+// takes precedence over DeviceID. PixelFormat is a
+// FOURCC such as "MJPG" or "YUYV". Zero Width/Height/FPS and an empty
+// PixelFormat keep the driver default. ReconnectTimeout bounds how long a
+// lost device is retried before the stream ends; zero retries until the
+// stream is cancelled.
+type Options struct {
+	URL              string
+	DeviceID         int
+	DevicePath       string
+	Width            int
+	Height           int
+	FPS              int
+	PixelFormat      string
+	ReconnectTimeout time.Duration
+}
+
+type EventKind int
+
+const (
+	// EventOpened is reported when Stream opens the device, carrying the
+	// mode the driver actually negotiated.
+	EventOpened EventKind = iota
+	// EventDisconnected is reported when reads keep failing (device
+	// unplugged, system suspended) and the stream starts reconnecting.
+	EventDisconnected
+	// EventReconnected is reported when a reconnect attempt succeeds,
+	// carrying the newly negotiated mode.
+	EventReconnected
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case EventOpened:
+		return "opened"
+	case EventDisconnected:
+		return "disconnected"
+	case EventReconnected:
+		return "reconnected"
+	}
+	return "unknown"
+}
+
+// Event reports a Service state change. Events are delivered on a small
+// buffered channel and dropped if nobody is reading.
+type Event struct {
+	Kind EventKind
+	Mode Mode
 }
 
+// Service captures frames from a local webcam or a network camera.
 type Service struct {
-	deviceID int
+	opts   Options
+	events chan Event
+
+	mu     sync.Mutex
+	active *ActiveDevice
+	// stream counts Stream calls. A stream's goroutine may outlive its
+	// cancellation by a read or a reconnect attempt; what it reports after
+	// a later Stream call is stale and dropped.
+	stream uint64
+}
+
+func NewService(opts Options) *Service {
+	return &Service{opts: opts, events: make(chan Event, eventBufferSize)}
+}
+
+// Events returns the channel Service reports state changes on. It stays the
+// same across streams, but only carries the latest stream's events: those
+// still queued from an earlier stream are discarded when a new one starts.
+func (s *Service) Events() <-chan Event {
+	return s.events
+}
+
+// nextStream starts a new stream generation and discards the events queued
+// by the previous ones.
+func (s *Service) nextStream() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stream++
+	for {
+		select {
+		case <-s.events:
+		default:
+			return s.stream
+		}
+	}
+}
+
+func (s *Service) notify(stream uint64, ev Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if stream != s.stream {
+		return
+	}
+	select {
+	case s.events <- ev:
+	default:
+	}
+}
+
+// SetOptions changes the device and mode used by the next Stream call; a
+// stream that is already running keeps its settings until restarted.
+func (s *Service) SetOptions(opts Options) {
+	s.opts = opts
 }
 
-func NewService(deviceID int) *Service {
-	return &Service{deviceID: deviceID}
+// Active reports the local device the current stream reads from, for
+// ListDevices to leave alone. ok is false for a network camera and while no
+// stream is open.
+func (s *Service) Active() (ActiveDevice, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.active == nil {
+		return ActiveDevice{}, false
+	}
+	return *s.active, true
 }
 
+// setActive records the device stream opened with opts, or clears it when
+// vcap is nil.
+func (s *Service) setActive(stream uint64, opts Options, vcap *gocv.VideoCapture, mode Mode) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if stream != s.stream {
+		return
+	}
+	if vcap == nil || opts.URL != "" {
+		s.active = nil
+		return
+	}
+	s.active = &ActiveDevice{ID: opts.DeviceID, Path: opts.DevicePath, Backend: backendName(vcap), Mode: mode}
+}
+
+// Stream opens the device and delivers frames until ctx is cancelled. When
+// reads keep failing the device is closed and re-opened with exponential
+// backoff; the channel only closes early if that gives up.
 func (s *Service) Stream(ctx context.Context) (<-chan Frame, error) {
-	vcap, err := gocv.VideoCaptureDevice(s.deviceID)
+	opts := s.opts
+	stream := s.nextStream()
+	vcap, mode, err := open(opts)
 	if err != nil {
+		s.setActive(stream, opts, nil, Mode{})
 		return nil, err
 	}
+	s.setActive(stream, opts, vcap, mode)
+	s.notify(stream, Event{Kind: EventOpened, Mode: mode})
 
-	ch := make(chan Frame, 1)
+	out := newSender()
 	go func() {
-		defer vcap.Close()
-		defer close(ch)
-
-		frame := gocv.NewMat()
-		defer frame.Close()
+		defer func() {
+			if vcap != nil {
+				vcap.Close()
+			}
+		}()
+		defer out.close(ctx)
+		defer s.setActive(stream, opts, nil, Mode{})
 
+		failureLimit := maxReadFailures
+		if opts.URL != "" {
+			failureLimit = maxNetworkReadFailures
+		}
 		failures := 0
 		for {
 			select {
@@ -45,27 +230,62 @@ func (s *Service) Stream(ctx context.Context) (<-chan Frame, error) {
 			default:
 			}
 
+			frame := out.mat()
 			if ok := vcap.Read(&frame); !ok || frame.Empty() {
+				out.recycle(frame)
 				failures++
-				if failures >= maxReadFailures {
+				if failures < failureLimit {
+					time.Sleep(10 * time.Millisecond)
+					continue
+				}
+				failures = 0
+				vcap.Close()
+				s.setActive(stream, opts, nil, Mode{})
+				s.notify(stream, Event{Kind: EventDisconnected})
+				if vcap = s.reconnect(ctx, stream, opts); vcap == nil {
 					return
 				}
-				time.Sleep(10 * time.Millisecond)
 				continue
 			}
 			failures = 0
-
-			select {
-			case ch <- Frame{
-				Mat:    frame.Clone(),
-				Width:  frame.Cols(),
-				Height: frame.Rows(),
-			}:
-			case <-ctx.Done():
-				return
-			}
+			out.send(frame, time.Now())
 		}
 	}()
 
-	return ch, nil
+	return out.ch, nil
+}
+
+func open(opts Options) (*gocv.VideoCapture, Mode, error) {
+	if opts.URL != "" {
+		vcap, err := openURL(opts.URL)
+		if err != nil {
+			return nil, Mode{}, err
+		}
+		return vcap, negotiatedMode(vcap), nil
+	}
+	vcap, err := openDevice(opts.DeviceID, opts.DevicePath)
+	if err != nil {
+		if deviceExists(opts.DeviceID, opts.DevicePath) {
+			return nil, Mode{}, fmt.Errorf("%w: %s", ErrDeviceBusy, describeDevice(opts))
+		}
+		return nil, Mode{}, fmt.Errorf("%w: %s", ErrOpenFailed, describeDevice(opts))
+	}
+	applyMode(vcap, opts)
+
+	// Drivers often open a device another application is streaming from and
+	// only fail once capture starts, so probe with one read.
+	probe := gocv.NewMat()
+	defer probe.Close()
+	if !vcap.Read(&probe) || probe.Empty() {
+		vcap.Close()
+		return nil, Mode{}, fmt.Errorf("%w: %s delivers no frames", ErrDeviceBusy, describeDevice(opts))
+	}
+	return vcap, negotiatedMode(vcap), nil
+}
+
+func describeDevice(opts Options) string {
+	if opts.DevicePath != "" {
+		return opts.DevicePath
+	}
+	return fmt.Sprintf("camera %d", opts.DeviceID)
 }
@@ -0,0 +1,39 @@
+package camera
+
+import (
+	"image"
+	"math"
+)
+
+// Crop is a region of interest in normalized [0,1] coordinates of the frame
+// as the user sees it in the preview. The zero value means the full frame.
+type Crop struct {
+	X      float64
+	Y      float64
+	Width  float64
+	Height float64
+}
+
+// IsFull reports whether c covers the whole frame.
+func (c Crop) IsFull() bool {
+	return c == Crop{} || (c.X <= 0 && c.Y <= 0 && c.Width >= 1 && c.Height >= 1)
+}
+
+// Rect converts c to a pixel rectangle in a width×height frame. The preview
+// is a mirror image, so with mirrored set X is measured from the preview's
+// left edge, which is the frame's right edge.
+func (c Crop) Rect(width, height int, mirrored bool) image.Rectangle {
+	full := image.Rect(0, 0, width, height)
+	if c.IsFull() {
+		return full
+	}
+	x := c.X
+	if mirrored {
+		x = 1 - c.X - c.Width
+	}
+	x0 := int(math.Round(x * float64(width)))
+	y0 := int(math.Round(c.Y * float64(height)))
+	w := max(1, int(math.Round(c.Width*float64(width))))
+	h := max(1, int(math.Round(c.Height*float64(height))))
+	return image.Rect(x0, y0, x0+w, y0+h).Intersect(full)
+}
@@ -0,0 +1,29 @@
+package camera
+
+import (
+	"image"
+	"testing"
+)
+
+func TestCropRect(t *testing.T) {
+	tests := []struct {
+		name     string
+		crop     Crop
+		mirrored bool
+		want     image.Rectangle
+	}{
+		{"zero value", Crop{}, false, image.Rect(0, 0, 640, 480)},
+		{"covers frame", Crop{X: -0.1, Y: -0.1, Width: 1.2, Height: 1.2}, true, image.Rect(0, 0, 640, 480)},
+		{"center", Crop{X: 0.25, Y: 0.25, Width: 0.5, Height: 0.5}, false, image.Rect(160, 120, 480, 360)},
+		{"center mirrored", Crop{X: 0.25, Y: 0.25, Width: 0.5, Height: 0.5}, true, image.Rect(160, 120, 480, 360)},
+		{"left", Crop{Width: 0.25, Height: 1}, false, image.Rect(0, 0, 160, 480)},
+		{"preview left is frame right", Crop{Width: 0.25, Height: 1}, true, image.Rect(480, 0, 640, 480)},
+		{"clipped to frame", Crop{X: 0.8, Width: 0.5, Height: 1}, false, image.Rect(512, 0, 640, 480)},
+		{"at least one pixel", Crop{X: 0.5, Y: 0.5, Width: 0.0001, Height: 0.0001}, false, image.Rect(320, 240, 321, 241)},
+	}
+	for _, tt := range tests {
+		if got := tt.crop.Rect(640, 480, tt.mirrored); got != tt.want {
+			t.Errorf("%s: Rect = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package camera
+
+import (
+	"path/filepath"
+
+	"gocv.io/x/gocv"
+)
+
+// probeResolutions are the common modes ListDevices asks each device for;
+// a mode counts as supported when the driver reports it back unchanged.
+var probeResolutions = []Resolution{
+	{Width: 320, Height: 240},
+	{Width: 640, Height: 480},
+	{Width: 800, Height: 600},
+	{Width: 1280, Height: 720},
+	{Width: 1920, Height: 1080},
+}
+
+// Device describes a capture device found by ListDevices.
+type Device struct {
+	ID          int          `json:"id"`
+	Path        string       `json:"path,omitempty"`
+	Backend     string       `json:"backend"`
+	Resolutions []Resolution `json:"resolutions,omitempty"`
+}
+
+type Resolution struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// ActiveDevice is the local device a Service is streaming from, with the
+// backend that opened it and the mode it negotiated.
+type ActiveDevice struct {
+	ID      int
+	Path    string
+	Backend string
+	Mode    Mode
+}
+
+// matches reports whether candidate c is this device, by path where both
+// have one (following symlinks such as /dev/v4l/by-id) and by index
+// otherwise.
+func (d ActiveDevice) matches(c Device) bool {
+	if d.Path == "" || c.Path == "" {
+		return d.ID == c.ID
+	}
+	if d.Path == c.Path {
+		return true
+	}
+	a, errA := filepath.EvalSymlinks(d.Path)
+	b, errB := filepath.EvalSymlinks(c.Path)
+	return errA == nil && errB == nil && a == b
+}
+
+// ListDevices opens every candidate capture device in turn and reports the
+// ones that respond. The active device, if any, is not opened: probing sets
+// its resolution, which on V4L2 would renegotiate or steal the running
+// stream. It is reported with the mode it negotiated as its only resolution.
+// A device already streaming in another application may fail to open and is
+// then left out.
+func ListDevices(active *ActiveDevice) []Device {
+	var devices []Device
+	for _, c := range deviceCandidates() {
+		if active != nil && active.matches(c) {
+			devices = append(devices, Device{
+				ID:          c.ID,
+				Path:        c.Path,
+				Backend:     active.Backend,
+				Resolutions: []Resolution{{Width: active.Mode.Width, Height: active.Mode.Height}},
+			})
+			continue
+		}
+		vcap, err := openDevice(c.ID, c.Path)
+		if err != nil {
+			continue
+		}
+		devices = append(devices, Device{
+			ID:          c.ID,
+			Path:        c.Path,
+			Backend:     backendName(vcap),
+			Resolutions: supportedResolutions(vcap),
+		})
+		vcap.Close()
+	}
+	return devices
+}
+
+func backendName(vcap *gocv.VideoCapture) string {
+	api := gocv.VideoCaptureAPI(vcap.Get(gocv.VideoCaptureBackend))
+	return gocv.VideoRegistry.GetBackendName(api)
+}
+
+// openDevice opens a capture device by path when one is given (stable across
+// replugs on Linux), otherwise by index. Paths go to devicePathAPI directly:
+// left to choose, OpenCV may try FFmpeg or GStreamer first, which ignore the
+// requested size, fps and pixel format.
+func openDevice(id int, path string) (*gocv.VideoCapture, error) {
+	var (
+		vcap *gocv.VideoCapture
+		err  error
+	)
+	if path != "" {
+		vcap, err = gocv.VideoCaptureFileWithAPI(path, devicePathAPI)
+	} else {
+		vcap, err = gocv.VideoCaptureDevice(id)
+	}
+	if err != nil {
+		vcap.Close()
+		return nil, err
+	}
+	return vcap, nil
+}
+
+func supportedResolutions(vcap *gocv.VideoCapture) []Resolution {
+	var out []Resolution
+	for _, r := range probeResolutions {
+		vcap.Set(gocv.VideoCaptureFrameWidth, float64(r.Width))
+		vcap.Set(gocv.VideoCaptureFrameHeight, float64(r.Height))
+		got := Resolution{
+			Width:  int(vcap.Get(gocv.VideoCaptureFrameWidth)),
+			Height: int(vcap.Get(gocv.VideoCaptureFrameHeight)),
+		}
+		if got == r {
+			out = append(out, r)
+		}
+	}
+	return out
+}
@@ -0,0 +1,44 @@
+//go:build linux
+
+package camera
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gocv.io/x/gocv"
+)
+
+// devicePathAPI opens /dev/video* nodes.
+const devicePathAPI = gocv.VideoCaptureV4L2
+
+// deviceExists reports whether the device node is present, which tells a
+// busy device apart from a missing one when opening fails.
+func deviceExists(id int, path string) bool {
+	if path == "" {
+		path = fmt.Sprintf("/dev/video%d", id)
+	}
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// deviceCandidates lists /dev/video* nodes. Metadata-only nodes that many
+// UVC cameras expose alongside the capture node fail to open and are
+// skipped by ListDevices.
+func deviceCandidates() []Device {
+	paths, _ := filepath.Glob("/dev/video*")
+	var out []Device
+	for _, p := range paths {
+		id, err := strconv.Atoi(strings.TrimPrefix(p, "/dev/video"))
+		if err != nil {
+			continue
+		}
+		out = append(out, Device{ID: id, Path: p})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
@@ -0,0 +1,26 @@
+//go:build !linux
+
+package camera
+
+import "gocv.io/x/gocv"
+
+// devicePathAPI is left to OpenCV; device paths are a V4L2 notion.
+const devicePathAPI = gocv.VideoCaptureAny
+
+// maxProbeDevices bounds the index scan on platforms without a device
+// directory to list.
+const maxProbeDevices = 8
+
+// deviceExists can't tell without opening the device on these platforms, so
+// a failed open is always reported as ErrOpenFailed.
+func deviceExists(id int, path string) bool {
+	return false
+}
+
+func deviceCandidates() []Device {
+	out := make([]Device, 0, maxProbeDevices)
+	for id := 0; id < maxProbeDevices; id++ {
+		out = append(out, Device{ID: id})
+	}
+	return out
+}
@@ -0,0 +1,79 @@
+package camera
+
+import (
+	"context"
+	"time"
+
+	"gocv.io/x/gocv"
+)
+
+// defaultFileFPS paces playback when the container doesn't report a frame
+// rate (common for image sequences).
+const defaultFileFPS = 30.0
+
+// FileSource plays back a recorded video file, or a numbered image sequence
+// such as "frames/img_%04d.png", at the recording's native frame rate. It lets
+// the full pipeline run without a webcam, e.g. to reproduce a user's report.
+type FileSource struct {
+	path string
+	loop bool
+}
+
+// NewFileSource returns a source that replays path. With loop set, playback
+// rewinds to the first frame instead of ending the stream.
+func NewFileSource(path string, loop bool) *FileSource {
+	return &FileSource{path: path, loop: loop}
+}
+
+func (s *FileSource) Stream(ctx context.Context) (<-chan Frame, error) {
+	vcap, err := gocv.VideoCaptureFile(s.path)
+	if err != nil {
+		vcap.Close()
+		return nil, err
+	}
+
+	fps := vcap.Get(gocv.VideoCaptureFPS)
+	if fps <= 0 {
+		fps = defaultFileFPS
+	}
+	interval := time.Duration(float64(time.Second) / fps)
+
+	out := newSender()
+	go func() {
+		defer vcap.Close()
+		defer out.close(ctx)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			frame := out.mat()
+			if !s.read(vcap, &frame) {
+				out.recycle(frame)
+				return
+			}
+
+			select {
+			case now := <-ticker.C:
+				out.send(frame, now)
+			case <-ctx.Done():
+				out.recycle(frame)
+				return
+			}
+		}
+	}()
+
+	return out.ch, nil
+}
+
+// read reads the next frame, rewinding to the start when looping.
+func (s *FileSource) read(vcap *gocv.VideoCapture, frame *gocv.Mat) bool {
+	if vcap.Read(frame) && !frame.Empty() {
+		return true
+	}
+	if !s.loop {
+		return false
+	}
+	vcap.Set(gocv.VideoCapturePosFrames, 0)
+	return vcap.Read(frame) && !frame.Empty()
+}
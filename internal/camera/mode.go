@@ -0,0 +1,41 @@
+package camera
+
+import (
+	"math"
+
+	"gocv.io/x/gocv"
+)
+
+// Mode is the capture mode a device actually negotiated, which may differ
+// from what Options requested.
+type Mode struct {
+	Width       int     `json:"width"`
+	Height      int     `json:"height"`
+	FPS         float64 `json:"fps"`
+	PixelFormat string  `json:"pixelFormat"`
+}
+
+// applyMode requests the configured capture mode before streaming starts.
+// The pixel format goes first: V4L2 drivers pick the resolutions and frame
+// rates they offer per format. Zero values keep the driver default.
+func applyMode(vcap *gocv.VideoCapture, opts Options) {
+	if opts.PixelFormat != "" {
+		vcap.Set(gocv.VideoCaptureFOURCC, vcap.ToCodec(opts.PixelFormat))
+	}
+	if opts.Width > 0 && opts.Height > 0 {
+		vcap.Set(gocv.VideoCaptureFrameWidth, float64(opts.Width))
+		vcap.Set(gocv.VideoCaptureFrameHeight, float64(opts.Height))
+	}
+	if opts.FPS > 0 {
+		vcap.Set(gocv.VideoCaptureFPS, float64(opts.FPS))
+	}
+}
+
+func negotiatedMode(vcap *gocv.VideoCapture) Mode {
+	return Mode{
+		Width:       int(vcap.Get(gocv.VideoCaptureFrameWidth)),
+		Height:      int(vcap.Get(gocv.VideoCaptureFrameHeight)),
+		FPS:         math.Round(vcap.Get(gocv.VideoCaptureFPS)*100) / 100,
+		PixelFormat: vcap.CodecString(),
+	}
+}
@@ -0,0 +1,45 @@
+package camera
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"gocv.io/x/gocv"
+)
+
+// FFmpeg timeouts for network streams. gocv has no names for these
+// properties (cv::CAP_PROP_OPEN_TIMEOUT_MSEC / CAP_PROP_READ_TIMEOUT_MSEC).
+const (
+	capPropOpenTimeoutMsec gocv.VideoCaptureProperties = 53
+	capPropReadTimeoutMsec gocv.VideoCaptureProperties = 54
+
+	networkOpenTimeout = 5 * time.Second
+	// networkReadTimeout bounds a read on a stalled stream. A stream that
+	// times out is treated as disconnected after maxNetworkReadFailures
+	// such reads instead of maxReadFailures, which assumes reads fail fast.
+	networkReadTimeout     = 3 * time.Second
+	maxNetworkReadFailures = 2
+)
+
+// openURL opens an MJPEG-over-HTTP or RTSP stream through FFmpeg. Errors
+// never include the URL's password.
+func openURL(rawURL string) (*gocv.VideoCapture, error) {
+	vcap, err := gocv.VideoCaptureFileWithAPIParams(rawURL, gocv.VideoCaptureFFmpeg, []gocv.VideoCaptureProperties{
+		capPropOpenTimeoutMsec, gocv.VideoCaptureProperties(networkOpenTimeout.Milliseconds()),
+		capPropReadTimeoutMsec, gocv.VideoCaptureProperties(networkReadTimeout.Milliseconds()),
+	})
+	if err != nil {
+		vcap.Close()
+		return nil, fmt.Errorf("%w: stream %s", ErrOpenFailed, redactURL(rawURL))
+	}
+	return vcap, nil
+}
+
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "(invalid URL)"
+	}
+	return u.Redacted()
+}
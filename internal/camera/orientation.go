@@ -0,0 +1,28 @@
+package camera
+
+import "gocv.io/x/gocv"
+
+// Rotate returns f turned clockwise by degrees (90, 180 or 270) so a
+// sideways or upside-down camera yields an upright image. The pixels are
+// written to dst, which the caller owns; the result shares f's metadata and
+// must not be released — release f instead. Any other angle returns f as is.
+func Rotate(f Frame, degrees int, dst *gocv.Mat) Frame {
+	var flag gocv.RotateFlag
+	switch degrees {
+	case 90:
+		flag = gocv.Rotate90Clockwise
+	case 180:
+		flag = gocv.Rotate180Clockwise
+	case 270:
+		flag = gocv.Rotate90CounterClockwise
+	default:
+		return f
+	}
+	gocv.Rotate(f.Mat, dst, flag)
+	out := f
+	out.Mat = *dst
+	out.Width = dst.Cols()
+	out.Height = dst.Rows()
+	out.pool = nil
+	return out
+}
@@ -0,0 +1,51 @@
+package camera
+
+import (
+	"sync"
+
+	"gocv.io/x/gocv"
+)
+
+// maxPooledMats covers the Mats in flight at once: one being read into, one
+// waiting in the channel and one being processed by the consumer.
+const maxPooledMats = 4
+
+// matPool recycles frame Mats between a capture goroutine and the consumer so
+// steady-state capture allocates nothing. It is shared by both goroutines,
+// hence the mutex; Mats returned after close are freed instead.
+type matPool struct {
+	mu     sync.Mutex
+	free   []gocv.Mat
+	closed bool
+}
+
+func (p *matPool) get() gocv.Mat {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if n := len(p.free); n > 0 {
+		m := p.free[n-1]
+		p.free = p.free[:n-1]
+		return m
+	}
+	return gocv.NewMat()
+}
+
+func (p *matPool) put(m gocv.Mat) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed || len(p.free) >= maxPooledMats {
+		m.Close()
+		return
+	}
+	p.free = append(p.free, m)
+}
+
+func (p *matPool) close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closed = true
+	for _, m := range p.free {
+		m.Close()
+	}
+	p.free = nil
+}
@@ -0,0 +1,44 @@
+package camera
+
+import (
+	"context"
+	"time"
+
+	"gocv.io/x/gocv"
+)
+
+const (
+	reconnectInitialDelay = 250 * time.Millisecond
+	reconnectMaxDelay     = 5 * time.Second
+)
+
+// reconnect re-opens the device described by opts, doubling the delay between
+// attempts up to reconnectMaxDelay. USB cameras typically reappear a second
+// or two after being replugged or after the system resumes; network cameras
+// after Wi-Fi drops out or the phone app is brought back to the foreground. It returns nil
+// once ctx is cancelled or opts.ReconnectTimeout has elapsed.
+func (s *Service) reconnect(ctx context.Context, stream uint64, opts Options) *gocv.VideoCapture {
+	start := time.Now()
+	delay := reconnectInitialDelay
+	for {
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+
+		vcap, mode, err := open(opts)
+		if err == nil {
+			s.setActive(stream, opts, vcap, mode)
+			s.notify(stream, Event{Kind: EventReconnected, Mode: mode})
+			return vcap
+		}
+
+		if opts.ReconnectTimeout > 0 && time.Since(start) >= opts.ReconnectTimeout {
+			return nil
+		}
+		delay = min(delay*2, reconnectMaxDelay)
+	}
+}
@@ -0,0 +1,72 @@
+package camera
+
+import (
+	"context"
+	"time"
+
+	"gocv.io/x/gocv"
+)
+
+// sender hands captured frames to the consumer with latest-frame-wins
+// semantics: if the consumer hasn't picked up the previous frame yet, that
+// stale frame is pulled back, recycled and counted as dropped, so the
+// consumer always gets the newest frame and the capture loop never blocks.
+type sender struct {
+	ch      chan Frame
+	pool    *matPool
+	seq     uint64
+	dropped uint64
+}
+
+func newSender() *sender {
+	return &sender{ch: make(chan Frame, 1), pool: &matPool{}}
+}
+
+// mat returns a pooled Mat for the capture loop to read into. It goes back
+// to the pool via send (as part of a Frame) or recycle.
+func (s *sender) mat() gocv.Mat {
+	return s.pool.get()
+}
+
+// recycle returns a Mat obtained from mat that won't be sent, e.g. after a
+// failed read.
+func (s *sender) recycle(m gocv.Mat) {
+	s.pool.put(m)
+}
+
+// send delivers mat, captured at capturedAt, taking ownership of it. Only
+// the capture goroutine sends, so once the stale frame (if any) is pulled
+// the slot is guaranteed free.
+func (s *sender) send(mat gocv.Mat, capturedAt time.Time) {
+	s.seq++
+	select {
+	case stale := <-s.ch:
+		stale.Release()
+		s.dropped++
+	default:
+	}
+	s.ch <- Frame{
+		Mat:        mat,
+		Width:      mat.Cols(),
+		Height:     mat.Rows(),
+		CapturedAt: capturedAt,
+		Seq:        s.seq,
+		Dropped:    s.dropped,
+		pool:       s.pool,
+	}
+}
+
+// close ends the stream. On cancellation nobody is going to read the
+// buffered frame, so it is released here; a stream that simply ran out
+// leaves it for the consumer.
+func (s *sender) close(ctx context.Context) {
+	if ctx.Err() != nil {
+		select {
+		case f := <-s.ch:
+			f.Release()
+		default:
+		}
+	}
+	close(s.ch)
+	s.pool.close()
+}
@@ -0,0 +1,310 @@
+package camera
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"image"
+	"image/color"
+	"math"
+	"math/rand"
+	"os"
+	"time"
+
+	"gocv.io/x/gocv"
+)
+
+// Segment kinds of a synthetic path.
+const (
+	SegmentLine   = "line"
+	SegmentCircle = "circle"
+	SegmentHold   = "hold"
+)
+
+const (
+	defaultSyntheticWidth  = 640
+	defaultSyntheticHeight = 480
+	defaultSyntheticFPS    = 30
+	defaultTargetSize      = 48
+)
+
+// PathSegment moves the synthetic target for Frames frames, starting where
+// the previous segment ended (SyntheticOptions.Start for the first). A line
+// travels to To; a circle orbits Center (the start point sets the radius and
+// starting angle) for Turns turns, counterclockwise when positive; a hold
+// stays put.
+//
+// Jitter adds up to ±Jitter px of noise to every frame's position. Occluded
+// hides the target behind a flat patch for the whole segment. Brightness
+// ramps the whole frame's brightness from LightFrom to LightTo (1 is
+// unchanged; zero values mean 1).
+type PathSegment struct {
+	Kind      string      `json:"kind"`
+	Frames    int         `json:"frames"`
+	To        image.Point `json:"to"`
+	Center    image.Point `json:"center"`
+	Turns     float64     `json:"turns"`
+	Jitter    int         `json:"jitter"`
+	Occluded  bool        `json:"occluded"`
+	LightFrom float64     `json:"lightFrom"`
+	LightTo   float64     `json:"lightTo"`
+}
+
+// SyntheticOptions configures a SyntheticSource. Zero sizes and FPS use
+// 640×480 at 30 fps with a 48 px target; Seed fixes the textures and jitter.
+type SyntheticOptions struct {
+	Width      int           `json:"width"`
+	Height     int           `json:"height"`
+	FPS        int           `json:"fps"`
+	TargetSize int           `json:"targetSize"`
+	Start      image.Point   `json:"start"`
+	Path       []PathSegment `json:"path"`
+	Seed       int64         `json:"seed"`
+	Loop       bool          `json:"loop"`
+}
+
+// Truth is the ground truth for one synthetic frame: where the target's
+// center was drawn, whether it was visible and the brightness applied.
+type Truth struct {
+	X, Y       int
+	Visible    bool
+	Brightness float64
+}
+
+// SyntheticSource renders a textured target moving along a scripted path over
+// a textured background, with exactly known positions, so the tracker and
+// mouse can be checked without a camera.
+type SyntheticSource struct {
+	opts       SyntheticOptions
+	truth      []Truth
+	background gocv.Mat
+	target     gocv.Mat
+}
+
+// NewSyntheticSource precomputes the path and textures. Close frees them.
+func NewSyntheticSource(opts SyntheticOptions) (*SyntheticSource, error) {
+	if opts.Width <= 0 || opts.Height <= 0 {
+		opts.Width, opts.Height = defaultSyntheticWidth, defaultSyntheticHeight
+	}
+	if opts.FPS <= 0 {
+		opts.FPS = defaultSyntheticFPS
+	}
+	if opts.TargetSize <= 0 {
+		opts.TargetSize = defaultTargetSize
+	}
+	if opts.TargetSize > opts.Width || opts.TargetSize > opts.Height {
+		return nil, errors.New("camera: synthetic target larger than frame")
+	}
+	if len(opts.Path) == 0 {
+		opts.Path = DemoPath(opts.Width, opts.Height)
+		if opts.Start == (image.Point{}) {
+			opts.Start = image.Pt(opts.Width/2, opts.Height/2)
+		}
+	}
+
+	truth := samplePath(opts)
+	if len(truth) == 0 {
+		return nil, errors.New("camera: synthetic path has no frames")
+	}
+	rng := rand.New(rand.NewSource(opts.Seed))
+	return &SyntheticSource{
+		opts:       opts,
+		truth:      truth,
+		background: texture(rng, opts.Width, opts.Height, 9),
+		target:     texture(rng, opts.TargetSize, opts.TargetSize, 0),
+	}, nil
+}
+
+// LoadSyntheticOptions reads SyntheticOptions from a JSON script file.
+func LoadSyntheticOptions(path string) (SyntheticOptions, error) {
+	var opts SyntheticOptions
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return opts, err
+	}
+	err = json.Unmarshal(data, &opts)
+	return opts, err
+}
+
+// DemoPath exercises every segment kind once on a width×height frame,
+// starting from and returning to the center.
+func DemoPath(width, height int) []PathSegment {
+	cx, cy := width/2, height/2
+	dx, dy := width/5, height/5
+	return []PathSegment{
+		{Kind: SegmentHold, Frames: 30},
+		{Kind: SegmentLine, Frames: 45, To: image.Pt(cx+dx, cy)},
+		{Kind: SegmentCircle, Frames: 90, Center: image.Pt(cx, cy), Turns: 1},
+		{Kind: SegmentLine, Frames: 45, To: image.Pt(cx, cy-dy)},
+		{Kind: SegmentHold, Frames: 30, Jitter: 2},
+		{Kind: SegmentHold, Frames: 15, Occluded: true},
+		{Kind: SegmentLine, Frames: 30, To: image.Pt(cx, cy), LightFrom: 1, LightTo: 0.5},
+		{Kind: SegmentHold, Frames: 30, LightFrom: 0.5, LightTo: 1.3},
+		{Kind: SegmentLine, Frames: 30, To: image.Pt(cx, cy), LightFrom: 1.3, LightTo: 1},
+	}
+}
+
+// Frames is the number of frames in one pass of the path.
+func (s *SyntheticSource) Frames() int {
+	return len(s.truth)
+}
+
+// Truth returns the ground truth for the frame with the given Frame.Seq.
+func (s *SyntheticSource) Truth(seq uint64) Truth {
+	if seq == 0 {
+		seq = 1
+	}
+	return s.truth[int((seq-1)%uint64(len(s.truth)))]
+}
+
+// Render draws frame i of the path into dst and returns its ground truth.
+func (s *SyntheticSource) Render(i int, dst *gocv.Mat) Truth {
+	t := s.truth[i%len(s.truth)]
+	s.background.CopyTo(dst)
+
+	half := s.opts.TargetSize / 2
+	rect := image.Rect(t.X-half, t.Y-half, t.X-half+s.opts.TargetSize, t.Y-half+s.opts.TargetSize)
+	if t.Visible {
+		roi := dst.Region(rect)
+		s.target.CopyTo(&roi)
+		roi.Close()
+	} else {
+		_ = gocv.Rectangle(dst, rect, color.RGBA{R: 96, G: 96, B: 96}, -1)
+	}
+	if t.Brightness != 1 {
+		_ = dst.ConvertToWithParams(dst, dst.Type(), float32(t.Brightness), 0)
+	}
+	return t
+}
+
+// Stream renders the path at opts.FPS. Frame i of a pass is delivered with
+// Seq i+1 (plus earlier passes when looping), which Truth maps back.
+func (s *SyntheticSource) Stream(ctx context.Context) (<-chan Frame, error) {
+	interval := time.Second / time.Duration(s.opts.FPS)
+	out := newSender()
+	go func() {
+		defer out.close(ctx)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for i := 0; s.opts.Loop || i < len(s.truth); i++ {
+			frame := out.mat()
+			s.Render(i, &frame)
+
+			select {
+			case now := <-ticker.C:
+				out.send(frame, now)
+			case <-ctx.Done():
+				out.recycle(frame)
+				return
+			}
+		}
+	}()
+	return out.ch, nil
+}
+
+func (s *SyntheticSource) Close() {
+	s.background.Close()
+	s.target.Close()
+}
+
+// samplePath expands the path into per-frame ground truth, keeping the
+// target fully inside the frame.
+func samplePath(opts SyntheticOptions) []Truth {
+	half := opts.TargetSize / 2
+	fit := func(p image.Point) image.Point {
+		return image.Pt(
+			clampInt(p.X, half, opts.Width-opts.TargetSize+half),
+			clampInt(p.Y, half, opts.Height-opts.TargetSize+half),
+		)
+	}
+
+	var truth []Truth
+	pos := fit(opts.Start)
+	for si, seg := range opts.Path {
+		// Jitter is seeded per segment so editing one segment doesn't
+		// change the noise in the others.
+		rng := rand.New(rand.NewSource(opts.Seed + int64(si) + 1))
+		from := pos
+		lightFrom, lightTo := seg.LightFrom, seg.LightTo
+		if lightFrom == 0 {
+			lightFrom = 1
+		}
+		if lightTo == 0 {
+			lightTo = 1
+		}
+		radius := math.Hypot(float64(from.X-seg.Center.X), float64(from.Y-seg.Center.Y))
+		angle0 := math.Atan2(float64(from.Y-seg.Center.Y), float64(from.X-seg.Center.X))
+
+		for f := 1; f <= seg.Frames; f++ {
+			k := float64(f) / float64(seg.Frames)
+			switch seg.Kind {
+			case SegmentLine:
+				pos = image.Pt(
+					from.X+int(math.Round(float64(seg.To.X-from.X)*k)),
+					from.Y+int(math.Round(float64(seg.To.Y-from.Y)*k)),
+				)
+			case SegmentCircle:
+				// Image y points down, so the angle decreases for
+				// counterclockwise turns on screen.
+				a := angle0 - 2*math.Pi*seg.Turns*k
+				pos = image.Pt(
+					seg.Center.X+int(math.Round(radius*math.Cos(a))),
+					seg.Center.Y+int(math.Round(radius*math.Sin(a))),
+				)
+			}
+			drawn := pos
+			if seg.Jitter > 0 {
+				drawn = drawn.Add(image.Pt(rng.Intn(2*seg.Jitter+1)-seg.Jitter, rng.Intn(2*seg.Jitter+1)-seg.Jitter))
+			}
+			drawn = fit(drawn)
+			truth = append(truth, Truth{
+				X:          drawn.X,
+				Y:          drawn.Y,
+				Visible:    !seg.Occluded,
+				Brightness: lightFrom + (lightTo-lightFrom)*k,
+			})
+		}
+		pos = fit(pos)
+	}
+	return truth
+}
+
+// texture returns a deterministic BGR noise image. A positive blur kernel
+// turns it into soft mottling (background); zero leaves a sharp pattern of
+// 4 px blocks that template matching locks onto (target).
+func texture(rng *rand.Rand, width, height, blur int) gocv.Mat {
+	const block = 4
+	data := make([]byte, width*height*3)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			i := (y*width + x) * 3
+			if blur == 0 && (x%block != 0 || y%block != 0) {
+				copy(data[i:i+3], data[((y/block*block)*width+x/block*block)*3:])
+				continue
+			}
+			data[i], data[i+1], data[i+2] = byte(rng.Intn(256)), byte(rng.Intn(256)), byte(rng.Intn(256))
+		}
+	}
+	wrapped, _ := gocv.NewMatFromBytes(height, width, gocv.MatTypeCV8UC3, data)
+	defer wrapped.Close()
+
+	out := gocv.NewMat()
+	if blur > 0 {
+		gocv.GaussianBlur(wrapped, &out, image.Pt(blur, blur), 0, 0, gocv.BorderReflect)
+	} else {
+		wrapped.CopyTo(&out)
+	}
+	return out
+}
+
+func clampInt(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
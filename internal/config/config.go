@@ -3,36 +3,138 @@ package config
 import (
 	"encoding/json"
 	"log"
+	"net/url"
 	"os"
 	"path/filepath"
 )
 
 const (
-	DefaultTemplateSizePx = 45
-	DefaultGainMultiplier = 8.0
-	DefaultSmoothing      = 0.30
-	DefaultDwellTimeMs    = 500
+	DefaultTemplateSizePx      = 45
+	DefaultGainMultiplier      = 8.0
+	DefaultSmoothing           = 0.30
+	DefaultDwellTimeMs         = 500
+	DefaultReconnectTimeoutSec = 60
+	DefaultPreprocessGamma     = 1.0
+	DefaultAdaptiveRate        = 0.05
+	DefaultAdaptiveMinScore    = 0.85
+	// DefaultReacquireAfterFrames is half a second at 30 fps.
+	DefaultReacquireAfterFrames = 15
+	DefaultScoreThreshold       = 0.68
+	DefaultSearchMargin         = 2.0
+	// DefaultPoseYawRangeDeg and DefaultPosePitchRangeDeg span the screen in
+	// absolute pointing mode until the head's range is calibrated: a turn
+	// of this many degrees from facing the camera reaches a screen edge.
+	DefaultPoseYawRangeDeg   = 20.0
+	DefaultPosePitchRangeDeg = 12.0
+	// MinPoseSpanDeg is the narrowest yaw or pitch range accepted; anything
+	// tighter would turn every tremor into a jump across the screen.
+	MinPoseSpanDeg = 5.0
+)
+
+// Pointing modes accepted in Params.PointingMode.
+const (
+	// PointingRelative moves the cursor by the tracked point's motion.
+	PointingRelative = "relative"
+	// PointingAbsolute maps the head's yaw and pitch onto the screen.
+	PointingAbsolute = "absolute"
+)
+
+// Tracker kinds accepted in Params.TrackerKind.
+const (
+	TrackerTemplate = "template"
+	TrackerFlow     = "flow"
+	// TrackerConstellation tracks several patches around the picked point.
+	TrackerConstellation = "constellation"
+	// TrackerMarker follows a colored sticker the user wears.
+	TrackerMarker = "marker"
+)
+
+// Capture pixel formats accepted in Params.CapturePixelFormat.
+const (
+	PixelFormatMJPG = "MJPG"
+	PixelFormatYUYV = "YUYV"
+)
+
+// Equalization modes accepted in Params.PreprocessEqualize.
+const (
+	EqualizeHistogram = "histogram"
+	EqualizeCLAHE     = "clahe"
 )
 
 // Params is persisted as JSON. Fields removed from this struct (e.g. the
 // short-lived configurable-hotkey experiment) are simply ignored by
 // json.Unmarshal in older config.json files — no migration needed.
 type Params struct {
-	TemplateSizePx    int     `json:"templateSizePx"`
-	GainMultiplier    float64 `json:"gainMultiplier"`
-	Smoothing         float64 `json:"smoothing"`
-	DwellEnabled      bool    `json:"dwellEnabled"`
-	DwellTimeMs       int     `json:"dwellTimeMs"`
-	AutoStart         bool    `json:"autoStart"`
-	RightClickEnabled bool    `json:"rightClickEnabled"`
+	TrackerKind          string  `json:"trackerKind"`
+	TemplateSizePx       int     `json:"templateSizePx"`
+	ScoreThreshold       float64 `json:"scoreThreshold"`
+	SearchMargin         float64 `json:"searchMargin"`
+	AdaptiveTemplate     bool    `json:"adaptiveTemplate"`
+	AdaptiveRate         float64 `json:"adaptiveRate"`
+	AdaptiveMinScore     float64 `json:"adaptiveMinScore"`
+	ScaleMin             float64 `json:"scaleMin"`
+	ScaleMax             float64 `json:"scaleMax"`
+	RotationMaxDeg       int     `json:"rotationMaxDeg"`
+	PredictiveSearch     bool    `json:"predictiveSearch"`
+	ReacquireAfterFrames int     `json:"reacquireAfterFrames"`
+	AutoPickFace         bool    `json:"autoPickFace"`
+	PointingMode         string  `json:"pointingMode"`
+	HeadPoseModelPath    string  `json:"headPoseModelPath"`
+	PoseYawMin           float64 `json:"poseYawMin"`
+	PoseYawMax           float64 `json:"poseYawMax"`
+	PosePitchMin         float64 `json:"posePitchMin"`
+	PosePitchMax         float64 `json:"posePitchMax"`
+	GainMultiplier       float64 `json:"gainMultiplier"`
+	Smoothing            float64 `json:"smoothing"`
+	DwellEnabled         bool    `json:"dwellEnabled"`
+	DwellTimeMs          int     `json:"dwellTimeMs"`
+	AutoStart            bool    `json:"autoStart"`
+	RightClickEnabled    bool    `json:"rightClickEnabled"`
+	CameraDeviceID       int     `json:"cameraDeviceId"`
+	CameraDevicePath     string  `json:"cameraDevicePath"`
+	CameraURL            string  `json:"cameraUrl"`
+	CaptureWidth         int     `json:"captureWidth"`
+	CaptureHeight        int     `json:"captureHeight"`
+	CaptureFPS           int     `json:"captureFps"`
+	CapturePixelFormat   string  `json:"capturePixelFormat"`
+	ReconnectTimeoutSec  int     `json:"reconnectTimeoutSec"`
+	PreprocessEqualize   string  `json:"preprocessEqualize"`
+	PreprocessGamma      float64 `json:"preprocessGamma"`
+	PreprocessDenoise    bool    `json:"preprocessDenoise"`
+	PreprocessNormalize  bool    `json:"preprocessNormalize"`
+	PreviewProcessed     bool    `json:"previewProcessed"`
+	CropX                float64 `json:"cropX"`
+	CropY                float64 `json:"cropY"`
+	CropWidth            float64 `json:"cropWidth"`
+	CropHeight           float64 `json:"cropHeight"`
+	CameraRotation       int     `json:"cameraRotation"`
+	MirrorImage          bool    `json:"mirrorImage"`
 }
 
 func DefaultParams() Params {
 	return Params{
-		TemplateSizePx: DefaultTemplateSizePx,
-		GainMultiplier: DefaultGainMultiplier,
-		Smoothing:      DefaultSmoothing,
-		DwellTimeMs:    DefaultDwellTimeMs,
+		TrackerKind:          TrackerTemplate,
+		TemplateSizePx:       DefaultTemplateSizePx,
+		ScoreThreshold:       DefaultScoreThreshold,
+		SearchMargin:         DefaultSearchMargin,
+		AdaptiveRate:         DefaultAdaptiveRate,
+		AdaptiveMinScore:     DefaultAdaptiveMinScore,
+		ScaleMin:             1,
+		ScaleMax:             1,
+		ReacquireAfterFrames: DefaultReacquireAfterFrames,
+		PointingMode:         PointingRelative,
+		PoseYawMin:           -DefaultPoseYawRangeDeg,
+		PoseYawMax:           DefaultPoseYawRangeDeg,
+		PosePitchMin:         -DefaultPosePitchRangeDeg,
+		PosePitchMax:         DefaultPosePitchRangeDeg,
+		GainMultiplier:       DefaultGainMultiplier,
+		Smoothing:            DefaultSmoothing,
+		DwellTimeMs:          DefaultDwellTimeMs,
+		ReconnectTimeoutSec:  DefaultReconnectTimeoutSec,
+		PreprocessGamma:      DefaultPreprocessGamma,
+		CropWidth:            1,
+		CropHeight:           1,
+		MirrorImage:          true,
 	}
 }
 
@@ -48,6 +150,12 @@ func NewManager(appName string) (*Manager, error) {
 	return &Manager{path: filepath.Join(dir, appName, "config.json")}, nil
 }
 
+// Dir returns the directory config.json lives in, which also holds other
+// per-user data such as recorded sessions.
+func (m *Manager) Dir() string {
+	return filepath.Dir(m.path)
+}
+
 func (m *Manager) Load() (Params, error) {
 	data, err := os.ReadFile(m.path)
 	if err != nil {
@@ -61,9 +169,54 @@ func (m *Manager) Load() (Params, error) {
 		log.Printf("config: failed to parse %s, using defaults: %v", m.path, err)
 		return DefaultParams(), nil
 	}
+	return Sanitize(p), nil
+}
+
+// Sanitize replaces unknown or out-of-range values in p with their defaults,
+// so neither a hand-edited config.json nor a settings update from the UI can
+// hand the tracker or the mouse a value they don't expect.
+func Sanitize(p Params) Params {
+	switch p.TrackerKind {
+	case TrackerTemplate, TrackerFlow, TrackerConstellation, TrackerMarker:
+	default:
+		p.TrackerKind = TrackerTemplate
+	}
 	if p.TemplateSizePx <= 0 {
 		p.TemplateSizePx = DefaultTemplateSizePx
 	}
+	if p.ScoreThreshold < 0.3 || p.ScoreThreshold > 0.95 {
+		p.ScoreThreshold = DefaultScoreThreshold
+	}
+	if p.SearchMargin < 1 || p.SearchMargin > 5 {
+		p.SearchMargin = DefaultSearchMargin
+	}
+	if p.AdaptiveRate <= 0 || p.AdaptiveRate > 0.5 {
+		p.AdaptiveRate = DefaultAdaptiveRate
+	}
+	if p.AdaptiveMinScore < 0.7 || p.AdaptiveMinScore > 0.99 {
+		p.AdaptiveMinScore = DefaultAdaptiveMinScore
+	}
+	if p.ScaleMin < 0.5 || p.ScaleMin > 1 {
+		p.ScaleMin = 1
+	}
+	if p.ScaleMax < 1 || p.ScaleMax > 2 {
+		p.ScaleMax = 1
+	}
+	if p.RotationMaxDeg < 0 || p.RotationMaxDeg > 30 {
+		p.RotationMaxDeg = 0
+	}
+	if p.ReacquireAfterFrames < 0 || p.ReacquireAfterFrames > 300 {
+		p.ReacquireAfterFrames = DefaultReacquireAfterFrames
+	}
+	if p.PointingMode != PointingAbsolute {
+		p.PointingMode = PointingRelative
+	}
+	if !validPoseRange(p.PoseYawMin, p.PoseYawMax) {
+		p.PoseYawMin, p.PoseYawMax = -DefaultPoseYawRangeDeg, DefaultPoseYawRangeDeg
+	}
+	if !validPoseRange(p.PosePitchMin, p.PosePitchMax) {
+		p.PosePitchMin, p.PosePitchMax = -DefaultPosePitchRangeDeg, DefaultPosePitchRangeDeg
+	}
 	if p.GainMultiplier <= 0 {
 		p.GainMultiplier = DefaultGainMultiplier
 	}
@@ -73,11 +226,45 @@ func (m *Manager) Load() (Params, error) {
 	if p.DwellTimeMs <= 0 {
 		p.DwellTimeMs = DefaultDwellTimeMs
 	}
-	return p, nil
+	if p.CameraDeviceID < 0 {
+		p.CameraDeviceID = 0
+	}
+	if p.CameraURL != "" && !validStreamURL(p.CameraURL) {
+		p.CameraURL = ""
+	}
+	if p.CaptureWidth <= 0 || p.CaptureHeight <= 0 {
+		p.CaptureWidth, p.CaptureHeight = 0, 0
+	}
+	if p.CaptureFPS < 0 {
+		p.CaptureFPS = 0
+	}
+	if p.ReconnectTimeoutSec < 0 {
+		p.ReconnectTimeoutSec = DefaultReconnectTimeoutSec
+	}
+	if p.PreprocessEqualize != EqualizeHistogram && p.PreprocessEqualize != EqualizeCLAHE {
+		p.PreprocessEqualize = ""
+	}
+	if p.PreprocessGamma < 0.2 || p.PreprocessGamma > 5 {
+		p.PreprocessGamma = DefaultPreprocessGamma
+	}
+	if p.CropWidth <= 0 || p.CropWidth > 1 || p.CropHeight <= 0 || p.CropHeight > 1 {
+		p.CropX, p.CropY, p.CropWidth, p.CropHeight = 0, 0, 1, 1
+	}
+	p.CropX = clampF(p.CropX, 0, 1-p.CropWidth)
+	p.CropY = clampF(p.CropY, 0, 1-p.CropHeight)
+	switch p.CameraRotation {
+	case 0, 90, 180, 270:
+	default:
+		p.CameraRotation = 0
+	}
+	if p.CapturePixelFormat != PixelFormatMJPG && p.CapturePixelFormat != PixelFormatYUYV {
+		p.CapturePixelFormat = ""
+	}
+	return p
 }
 
 func (m *Manager) Save(p Params) error {
-	if err := os.MkdirAll(filepath.Dir(m.path), 0755); err != nil {
+	if err := os.MkdirAll(m.Dir(), 0755); err != nil {
 		return err
 	}
 	data, err := json.MarshalIndent(p, "", "  ")
@@ -86,3 +273,33 @@ func (m *Manager) Save(p Params) error {
 	}
 	return os.WriteFile(m.path, data, 0644)
 }
+
+// validStreamURL accepts the network camera URLs the camera package can open:
+// MJPEG over HTTP(S) and RTSP.
+func validStreamURL(s string) bool {
+	u, err := url.Parse(s)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	switch u.Scheme {
+	case "http", "https", "rtsp", "rtsps":
+		return true
+	}
+	return false
+}
+
+// validPoseRange accepts a calibrated yaw or pitch range: within a quarter
+// turn either way and at least MinPoseSpanDeg wide.
+func validPoseRange(lo, hi float64) bool {
+	return lo >= -90 && hi <= 90 && hi-lo >= MinPoseSpanDeg
+}
+
+func clampF(v, lo, hi float64) float64 {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
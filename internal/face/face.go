@@ -0,0 +1,94 @@
+// Package face finds the user's face in a camera frame so a tracking target
+// can be picked without pointing at it. It uses OpenCV's stock frontal-face
+// Haar cascade, which is embedded in the binary.
+package face
+
+import (
+	_ "embed"
+	"errors"
+	"image"
+	"os"
+
+	"gocv.io/x/gocv"
+)
+
+//go:embed haarcascade_frontalface_default.xml
+var frontalFaceCascade []byte
+
+const (
+	// targetY places the picked point on the bridge of the nose, just below
+	// the eyes, as a fraction of the detected face's height. The eye corners
+	// and brows give the template texture, and the spot moves rigidly with
+	// the head.
+	targetY = 0.45
+	// minFaceDivisor sets the smallest face searched for to the frame's
+	// shorter side divided by it; a user sitting at the computer fills far
+	// more than that, and skipping smaller scales keeps detection fast.
+	minFaceDivisor  = 5
+	detectScaleStep = 1.1
+	detectNeighbors = 5
+)
+
+var errCascadeLoad = errors.New("face: cannot load face cascade")
+
+// Detector finds frontal faces. It is not safe for concurrent use.
+type Detector struct {
+	classifier gocv.CascadeClassifier
+}
+
+// NewDetector loads the embedded cascade. OpenCV only reads cascades from
+// files, so it is written to a temporary file first.
+func NewDetector() (*Detector, error) {
+	f, err := os.CreateTemp("", "open-camera-mouse-face-*.xml")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(frontalFaceCascade); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+
+	classifier := gocv.NewCascadeClassifier()
+	if !classifier.Load(f.Name()) {
+		classifier.Close()
+		return nil, errCascadeLoad
+	}
+	return &Detector{classifier: classifier}, nil
+}
+
+// Detect returns the largest face in frame, which is taken to be the user's.
+func (d *Detector) Detect(frame gocv.Mat) (image.Rectangle, bool) {
+	gray := gocv.NewMat()
+	defer gray.Close()
+	if frame.Channels() > 1 {
+		gocv.CvtColor(frame, &gray, gocv.ColorBGRToGray)
+	} else {
+		frame.CopyTo(&gray)
+	}
+	gocv.EqualizeHist(gray, &gray)
+
+	side := min(gray.Cols(), gray.Rows()) / minFaceDivisor
+	faces := d.classifier.DetectMultiScaleWithParams(gray, detectScaleStep, detectNeighbors, 0,
+		image.Pt(side, side), image.Point{})
+
+	var best image.Rectangle
+	for _, r := range faces {
+		if r.Dx()*r.Dy() > best.Dx()*best.Dy() {
+			best = r
+		}
+	}
+	return best, !best.Empty()
+}
+
+// Target is the point to track within a detected face.
+func Target(face image.Rectangle) image.Point {
+	return image.Pt(face.Min.X+face.Dx()/2, face.Min.Y+int(float64(face.Dy())*targetY))
+}
+
+func (d *Detector) Close() {
+	d.classifier.Close()
+}
@@ -11,6 +11,13 @@ const (
 	DeadzonePx    = 1.0
 	MaxSpeedPx    = 35.0
 	DwellRadiusPx = 30.0
+
+	// ReferenceFrameInterval is the frame spacing MaxSpeedPx and Smoothing
+	// are tuned for (30 fps); other intervals are scaled against it.
+	ReferenceFrameInterval = time.Second / 30
+	// maxFrameSteps caps how many reference frames one update may span, so
+	// the first frame after a stall doesn't fling the cursor.
+	maxFrameSteps = 4.0
 )
 
 type Params struct {
@@ -19,13 +26,43 @@ type Params struct {
 	DwellEnabled      bool
 	DwellTimeMs       int
 	RightClickEnabled bool
+	// TrueView is set when the camera image is a true (unmirrored) view of
+	// the user, so moving the head right moves the point left in the frame.
+	// That is the case config.Params.MirrorImage is on for: the preview
+	// mirrors such a camera.
+	TrueView bool
+	// YawMin..YawMax and PitchMin..PitchMax are the head angles, in
+	// degrees, that UpdateAbsolute spreads across the screen from edge to
+	// edge.
+	YawMin, YawMax     float64
+	PitchMin, PitchMax float64
+}
+
+// Output is what one Update did to the real cursor, for session recording.
+type Output struct {
+	Moved bool   `json:"moved"`
+	X     int    `json:"x"`
+	Y     int    `json:"y"`
+	Click string `json:"click,omitempty"`
+}
+
+// Backend is the cursor Mouse drives: the real one through robotgo, or a
+// VirtualCursor when replaying a session.
+type Backend interface {
+	Move(x, y int)
+	Click(right bool)
+	Location() (int, int)
+	// ScreenSize is the size of the screen the cursor moves on.
+	ScreenSize() (int, int)
 }
 
 type Mouse struct {
-	params Params
+	params  Params
+	backend Backend
 
 	lastX       float64
 	lastY       float64
+	lastAt      time.Time
 	smoothX     float64
 	smoothY     float64
 	initialized bool
@@ -37,7 +74,11 @@ type Mouse struct {
 }
 
 func New(params Params) *Mouse {
-	return &Mouse{params: params}
+	return NewWithBackend(params, robotgoBackend{})
+}
+
+func NewWithBackend(params Params, backend Backend) *Mouse {
+	return &Mouse{params: params, backend: backend}
 }
 
 func (m *Mouse) SetParams(params Params) {
@@ -51,29 +92,39 @@ func (m *Mouse) Reset() {
 	m.dwellRefSet = false
 }
 
-func (m *Mouse) Update(x, y int, lost bool) {
-	m.updateCursor(x, y, lost)
-	m.updateDwell(lost)
+// Update moves the cursor for a tracking point observed in a frame captured
+// at `at`. Speed limits and smoothing are applied per elapsed time rather
+// than per call, so dropped frames or a slower camera don't change how the
+// cursor feels.
+func (m *Mouse) Update(x, y float64, lost bool, at time.Time) Output {
+	var out Output
+	out.X, out.Y, out.Moved = m.updateCursor(x, y, lost, at)
+	out.Click = m.updateDwell(lost, at)
+	return out
 }
 
-func (m *Mouse) updateCursor(x, y int, lost bool) {
-	fx, fy := float64(x), float64(y)
-
+func (m *Mouse) updateCursor(fx, fy float64, lost bool, at time.Time) (int, int, bool) {
 	if lost || !m.initialized {
 		if !lost {
 			m.initialized = true
 		}
 		m.lastX = fx
 		m.lastY = fy
+		m.lastAt = at
 		m.smoothX = 0
 		m.smoothY = 0
-		return
+		return 0, 0, false
 	}
 
 	dx := m.lastX - fx
+	if !m.params.TrueView {
+		dx = -dx
+	}
 	dy := fy - m.lastY
+	steps := frameSteps(at.Sub(m.lastAt))
 	m.lastX = fx
 	m.lastY = fy
+	m.lastAt = at
 
 	if math.Abs(dx) < DeadzonePx {
 		dx = 0
@@ -82,63 +133,139 @@ func (m *Mouse) updateCursor(x, y int, lost bool) {
 		dy = 0
 	}
 
-	dx = clampF(dx, -MaxSpeedPx, MaxSpeedPx)
-	dy = clampF(dy, -MaxSpeedPx, MaxSpeedPx)
+	maxSpeed := MaxSpeedPx * steps
+	dx = clampF(dx, -maxSpeed, maxSpeed)
+	dy = clampF(dy, -maxSpeed, maxSpeed)
 
 	targetX := dx * m.params.GainMultiplier
 	targetY := dy * m.params.GainMultiplier
 
-	m.smoothX += (targetX - m.smoothX) * m.params.Smoothing
-	m.smoothY += (targetY - m.smoothY) * m.params.Smoothing
+	// Smoothing is the EMA coefficient for one reference frame; compound it
+	// over the frames this update actually spans.
+	alpha := 1 - math.Pow(1-m.params.Smoothing, steps)
+	m.smoothX += (targetX - m.smoothX) * alpha
+	m.smoothY += (targetY - m.smoothY) * alpha
 
-	curX, curY := position()
+	curX, curY := m.backend.Location()
 	newX := curX + int(math.Round(m.smoothX))
 	newY := curY + int(math.Round(m.smoothY))
-	move(newX, newY)
+	m.backend.Move(newX, newY)
+	return newX, newY, true
+}
+
+// UpdateAbsolute places the cursor for a head pose in absolute pointing
+// mode: yaw and pitch are mapped linearly onto the screen, the ends of
+// Params' ranges at its edges, and the cursor eases toward that spot with
+// the same per-elapsed-time smoothing Update uses. Gain and the speed limit
+// don't apply; the calibrated range sets how far the head has to turn.
+func (m *Mouse) UpdateAbsolute(yaw, pitch float64, lost bool, at time.Time) Output {
+	var out Output
+	out.X, out.Y, out.Moved = m.updateAbsolute(yaw, pitch, lost, at)
+	out.Click = m.updateDwell(lost, at)
+	return out
+}
+
+func (m *Mouse) updateAbsolute(yaw, pitch float64, lost bool, at time.Time) (int, int, bool) {
+	if lost {
+		m.initialized = false
+		return 0, 0, false
+	}
+
+	w, h := m.backend.ScreenSize()
+	u := rangeFraction(yaw, m.params.YawMin, m.params.YawMax)
+	if m.params.TrueView {
+		// Turning right swings the nose left in a true view.
+		u = 1 - u
+	}
+	// Looking up moves the cursor up.
+	v := 1 - rangeFraction(pitch, m.params.PitchMin, m.params.PitchMax)
+	targetX, targetY := u*float64(w-1), v*float64(h-1)
+
+	if !m.initialized {
+		m.initialized = true
+		m.smoothX, m.smoothY = targetX, targetY
+	} else {
+		alpha := 1 - math.Pow(1-m.params.Smoothing, frameSteps(at.Sub(m.lastAt)))
+		m.smoothX += (targetX - m.smoothX) * alpha
+		m.smoothY += (targetY - m.smoothY) * alpha
+	}
+	m.lastAt = at
+
+	newX, newY := int(math.Round(m.smoothX)), int(math.Round(m.smoothY))
+	m.backend.Move(newX, newY)
+	return newX, newY, true
 }
 
-func (m *Mouse) updateDwell(lost bool) {
+// updateDwell returns the button it clicked, if any.
+func (m *Mouse) updateDwell(lost bool, at time.Time) string {
 	if !m.params.DwellEnabled || lost {
 		m.dwellRefSet = false
-		return
+		return ""
 	}
 
-	curX, curY := position()
+	curX, curY := m.backend.Location()
 
 	if !m.dwellRefSet {
 		m.dwellRefX = curX
 		m.dwellRefY = curY
 		m.dwellRefSet = true
-		m.dwellStart = time.Now()
-		return
+		m.dwellStart = at
+		return ""
 	}
 
 	dist := math.Hypot(float64(curX-m.dwellRefX), float64(curY-m.dwellRefY))
 	if dist > DwellRadiusPx {
 		m.dwellRefX = curX
 		m.dwellRefY = curY
-		m.dwellStart = time.Now()
-		return
+		m.dwellStart = at
+		return ""
 	}
 
 	dwellTime := time.Duration(m.params.DwellTimeMs) * time.Millisecond
-	if time.Since(m.dwellStart) >= dwellTime {
-		m.dwellStart = time.Now()
-		click(m.params.RightClickEnabled)
+	if at.Sub(m.dwellStart) >= dwellTime {
+		m.dwellStart = at
+		m.backend.Click(m.params.RightClickEnabled)
+		if m.params.RightClickEnabled {
+			return "right"
+		}
+		return "left"
 	}
+	return ""
 }
 
-func move(x, y int) { robotgo.Move(x, y) }
+type robotgoBackend struct{}
 
-func click(rightClick bool) {
-	if rightClick {
+func (robotgoBackend) Move(x, y int) { robotgo.Move(x, y) }
+
+func (robotgoBackend) Click(right bool) {
+	if right {
 		robotgo.Click("right", false)
 		return
 	}
 	robotgo.Click("left", false)
 }
 
-func position() (int, int) { return robotgo.Location() }
+func (robotgoBackend) Location() (int, int) { return robotgo.Location() }
+
+func (robotgoBackend) ScreenSize() (int, int) { return robotgo.GetScreenSize() }
+
+// frameSteps expresses elapsed time in reference frames, falling back to one
+// frame when timestamps are missing or out of order.
+func frameSteps(elapsed time.Duration) float64 {
+	if elapsed <= 0 {
+		return 1
+	}
+	return math.Min(float64(elapsed)/float64(ReferenceFrameInterval), maxFrameSteps)
+}
+
+// rangeFraction is where v falls between lo and hi, from 0 to 1, clamped;
+// the middle for an empty range.
+func rangeFraction(v, lo, hi float64) float64 {
+	if hi <= lo {
+		return 0.5
+	}
+	return clampF((v-lo)/(hi-lo), 0, 1)
+}
 
 func clampF(v, lo, hi float64) float64 {
 	if v < lo {
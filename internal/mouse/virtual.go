@@ -0,0 +1,42 @@
+package mouse
+
+// VirtualCursor is an in-memory Backend for replaying sessions without
+// touching the real cursor. Like the OS cursor, it stays on a screen of the
+// given size.
+type VirtualCursor struct {
+	X, Y          int
+	Width, Height int
+	Clicks        int
+}
+
+// NewVirtualCursor returns a cursor at the center of a width×height screen.
+func NewVirtualCursor(width, height int) *VirtualCursor {
+	return &VirtualCursor{X: width / 2, Y: height / 2, Width: width, Height: height}
+}
+
+func (c *VirtualCursor) Move(x, y int) {
+	c.X = clampI(x, 0, c.Width-1)
+	c.Y = clampI(y, 0, c.Height-1)
+}
+
+func (c *VirtualCursor) Click(right bool) {
+	c.Clicks++
+}
+
+func (c *VirtualCursor) Location() (int, int) {
+	return c.X, c.Y
+}
+
+func (c *VirtualCursor) ScreenSize() (int, int) {
+	return c.Width, c.Height
+}
+
+func clampI(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
@@ -0,0 +1,148 @@
+package preprocess
+
+import (
+	"image"
+	"math"
+
+	"gocv.io/x/gocv"
+)
+
+// Equalization modes for Params.Equalize.
+const (
+	EqualizeOff       = ""
+	EqualizeHistogram = "histogram"
+	EqualizeCLAHE     = "clahe"
+)
+
+const (
+	claheClipLimit = 2.0
+	claheTileGrid  = 8
+	denoiseKernel  = 3
+
+	// normalizeTargetMean is the brightness auto-exposure normalization aims
+	// for; the gain it may apply is bounded so a black frame stays black.
+	normalizeTargetMean = 128.0
+	normalizeMinGain    = 0.5
+	normalizeMaxGain    = 4.0
+)
+
+type Params struct {
+	Equalize  string
+	Gamma     float64
+	Denoise   bool
+	Normalize bool
+}
+
+// Enabled reports whether any step would change the grayscale frame.
+func (p Params) Enabled() bool {
+	return p.Equalize != EqualizeOff || (p.Gamma > 0 && p.Gamma != 1) || p.Denoise || p.Normalize
+}
+
+// Processor turns a camera frame into the grayscale image tracking runs on:
+// denoise, brightness normalization, gamma, then histogram equalization, each
+// optional. It reuses its buffers between frames and, like the tracker, is
+// owned exclusively by the app goroutine.
+type Processor struct {
+	params Params
+
+	cur gocv.Mat
+	tmp gocv.Mat
+
+	clahe    gocv.CLAHE
+	hasCLAHE bool
+
+	gammaLUT gocv.Mat
+	lutGamma float64
+}
+
+func New(params Params) *Processor {
+	return &Processor{
+		params:   params,
+		cur:      gocv.NewMat(),
+		tmp:      gocv.NewMat(),
+		gammaLUT: gocv.NewMat(),
+	}
+}
+
+func (p *Processor) SetParams(params Params) {
+	p.params = params
+}
+
+// Enabled reports whether Apply would do more than convert to grayscale;
+// when it wouldn't, callers can hand the raw frame to the tracker instead.
+func (p *Processor) Enabled() bool {
+	return p.params.Enabled()
+}
+
+// Apply returns the preprocessed grayscale version of frame. The result is
+// owned by the Processor and only valid until the next Apply.
+func (p *Processor) Apply(frame gocv.Mat) gocv.Mat {
+	if frame.Channels() > 1 {
+		gocv.CvtColor(frame, &p.cur, gocv.ColorBGRToGray)
+	} else {
+		frame.CopyTo(&p.cur)
+	}
+
+	if p.params.Denoise {
+		gocv.MedianBlur(p.cur, &p.tmp, denoiseKernel)
+		p.swap()
+	}
+	if p.params.Normalize {
+		gain := normalizeGain(p.cur.Mean().Val1)
+		p.cur.ConvertToWithParams(&p.tmp, gocv.MatTypeCV8U, float32(gain), 0)
+		p.swap()
+	}
+	if p.params.Gamma > 0 && p.params.Gamma != 1 {
+		gocv.LUT(p.cur, p.lut(p.params.Gamma), &p.tmp)
+		p.swap()
+	}
+	switch p.params.Equalize {
+	case EqualizeHistogram:
+		gocv.EqualizeHist(p.cur, &p.tmp)
+		p.swap()
+	case EqualizeCLAHE:
+		if !p.hasCLAHE {
+			p.clahe = gocv.NewCLAHEWithParams(claheClipLimit, image.Pt(claheTileGrid, claheTileGrid))
+			p.hasCLAHE = true
+		}
+		p.clahe.Apply(p.cur, &p.tmp)
+		p.swap()
+	}
+	return p.cur
+}
+
+func (p *Processor) Close() {
+	p.cur.Close()
+	p.tmp.Close()
+	p.gammaLUT.Close()
+	if p.hasCLAHE {
+		p.clahe.Close()
+	}
+}
+
+func (p *Processor) swap() {
+	p.cur, p.tmp = p.tmp, p.cur
+}
+
+// lut returns the 256-entry lookup table for gamma, rebuilt only when gamma
+// changes. Gamma > 1 brightens shadows, matching the usual "gamma" slider.
+func (p *Processor) lut(gamma float64) gocv.Mat {
+	if p.lutGamma == gamma && !p.gammaLUT.Empty() {
+		return p.gammaLUT
+	}
+	p.gammaLUT.Close()
+	p.gammaLUT = gocv.NewMatWithSize(1, 256, gocv.MatTypeCV8U)
+	for i := 0; i < 256; i++ {
+		v := math.Pow(float64(i)/255, 1/gamma) * 255
+		p.gammaLUT.SetUCharAt(0, i, uint8(math.Round(v)))
+	}
+	p.lutGamma = gamma
+	return p.gammaLUT
+}
+
+func normalizeGain(mean float64) float64 {
+	if mean <= 0 {
+		return normalizeMaxGain
+	}
+	return math.Max(normalizeMinGain, math.Min(normalizeTargetMean/mean, normalizeMaxGain))
+}
@@ -18,6 +18,10 @@ type TrackingOverlay struct {
 	Y              int  `json:"y"`
 	TemplateSizePx int  `json:"templateSizePx"`
 	Lost           bool `json:"lost"`
+	// Match quality of the tracked point; see tracking.Result.
+	Score           float64 `json:"score"`
+	Sharpness       float64 `json:"sharpness"`
+	SecondBestRatio float64 `json:"secondBestRatio"`
 }
 
 type Frame struct {
@@ -29,21 +33,35 @@ type Frame struct {
 
 type Encoder struct {
 	lastEncode time.Time
+	mirror     bool
 }
 
-func NewEncoder() *Encoder {
-	return &Encoder{}
+// NewEncoder returns an encoder that flips frames horizontally when mirror
+// is set, so the preview behaves like a mirror for an ordinary webcam.
+func NewEncoder(mirror bool) *Encoder {
+	return &Encoder{mirror: mirror}
 }
 
+func (e *Encoder) SetMirror(mirror bool) {
+	e.mirror = mirror
+}
+
+// Encode rate-limits by capture time rather than wall time, so playback
+// and live capture produce the same preview cadence. A timestamp earlier
+// than the last encode (a restarted source) is treated as a fresh start.
 func (e *Encoder) Encode(frame camera.Frame, tracking *TrackingOverlay) *Frame {
-	if time.Since(e.lastEncode) < previewInterval {
+	if since := frame.CapturedAt.Sub(e.lastEncode); since >= 0 && since < previewInterval {
 		return nil
 	}
-	e.lastEncode = time.Now()
+	e.lastEncode = frame.CapturedAt
 
-	display := gocv.NewMat()
-	defer display.Close()
-	gocv.Flip(frame.Mat, &display, 1)
+	display := frame.Mat
+	if e.mirror {
+		flipped := gocv.NewMat()
+		defer flipped.Close()
+		gocv.Flip(frame.Mat, &flipped, 1)
+		display = flipped
+	}
 
 	buf, err := gocv.IMEncodeWithParams(gocv.JPEGFileExt, display, []int{gocv.IMWriteJpegQuality, jpegQuality})
 	if err != nil {
@@ -0,0 +1,246 @@
+// Package recorder writes a tracking session to disk so a user's report can
+// be looked at and replayed later. A session directory holds the raw camera
+// frames as MJPEG video segments plus events.jsonl, one Record per line.
+package recorder
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"open-camera-mouse/internal/camera"
+	"open-camera-mouse/internal/config"
+	"open-camera-mouse/internal/mouse"
+	"open-camera-mouse/internal/tracking"
+
+	"gocv.io/x/gocv"
+)
+
+const (
+	// EventsFile is the name of the record log inside a session directory.
+	EventsFile = "events.jsonl"
+
+	// Version is bumped whenever Record changes incompatibly.
+	Version = 1
+
+	// videoFPS is only the container's nominal rate; players may use it, but
+	// replay paces frames by Record.T instead.
+	videoFPS   = 30
+	videoCodec = "MJPG"
+
+	// maxDirAttempts bounds the suffixes tried when sessions start within
+	// the same second.
+	maxDirAttempts = 100
+)
+
+// Record types written to events.jsonl.
+const (
+	TypeSession = "session"
+	TypeFrame   = "frame"
+	TypeCommand = "command"
+	TypeCamera  = "camera"
+)
+
+// Record is one line of events.jsonl. Which fields are set depends on Type.
+//
+// Frame counts frames written so far: a frame record carries its own index,
+// while command and camera records carry the index of the next frame, i.e.
+// they took effect before that frame was processed.
+type Record struct {
+	Type  string        `json:"type"`
+	T     time.Duration `json:"t"`
+	Frame int           `json:"frame"`
+
+	// TypeSession
+	Version   int            `json:"version,omitempty"`
+	StartedAt *time.Time     `json:"startedAt,omitempty"`
+	Params    *config.Params `json:"params,omitempty"`
+
+	// TypeFrame. Segment names the video file holding the frame; a new one
+	// is started whenever the frame size changes.
+	Seq      uint64           `json:"seq,omitempty"`
+	Dropped  uint64           `json:"dropped,omitempty"`
+	Segment  string           `json:"segment,omitempty"`
+	Width    int              `json:"width,omitempty"`
+	Height   int              `json:"height,omitempty"`
+	Tracking *tracking.Result `json:"tracking,omitempty"`
+	Cursor   *mouse.Output    `json:"cursor,omitempty"`
+
+	// TypeCommand and TypeCamera. Params is reused for set-params commands.
+	Command string `json:"command,omitempty"`
+	X       int    `json:"x,omitempty"`
+	Y       int    `json:"y,omitempty"`
+	Enabled bool   `json:"enabled,omitempty"`
+	Event   string `json:"event,omitempty"`
+}
+
+// Recorder writes one session. It is not safe for concurrent use; app.App
+// only touches it from its run goroutine.
+type Recorder struct {
+	dir    string
+	start  time.Time
+	events *os.File
+	enc    *json.Encoder
+
+	video   *gocv.VideoWriter
+	segment string
+	width   int
+	height  int
+	frames  int
+}
+
+// Start creates a new timestamped session directory under baseDir and writes
+// the session header with the params in effect.
+func Start(baseDir string, params config.Params) (*Recorder, error) {
+	start := time.Now()
+	dir, err := newSessionDir(baseDir, start.Format("20060102-150405"))
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(filepath.Join(dir, EventsFile), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Recorder{dir: dir, start: start, events: f, enc: json.NewEncoder(f)}
+	if err := r.write(Record{Type: TypeSession, Version: Version, StartedAt: &start, Params: &params}); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return r, nil
+}
+
+// newSessionDir creates a directory named name under baseDir, or name-2,
+// name-3, … if it is taken, so a session started in the same second as
+// the last one never writes over files the other still has open.
+func newSessionDir(baseDir, name string) (string, error) {
+	if err := os.MkdirAll(baseDir, 0755); err != nil {
+		return "", err
+	}
+	for i := 1; i <= maxDirAttempts; i++ {
+		dir := filepath.Join(baseDir, name)
+		if i > 1 {
+			dir += fmt.Sprintf("-%d", i)
+		}
+		err := os.Mkdir(dir, 0755)
+		if err == nil {
+			return dir, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return "", err
+		}
+	}
+	return "", fmt.Errorf("recorder: no free session directory for %s", name)
+}
+
+// Dir returns the session directory.
+func (r *Recorder) Dir() string {
+	return r.dir
+}
+
+// Frame appends the raw (un-rotated, uncropped) captured frame together with
+// what the tracker and the cursor made of it. result and cursor are nil when
+// tracking was paused for the frame.
+func (r *Recorder) Frame(f camera.Frame, result *tracking.Result, cursor *mouse.Output) error {
+	if err := r.writeVideo(f); err != nil {
+		return err
+	}
+	err := r.write(Record{
+		Type:     TypeFrame,
+		T:        f.CapturedAt.Sub(r.start),
+		Frame:    r.frames,
+		Seq:      f.Seq,
+		Dropped:  f.Dropped,
+		Segment:  r.segment,
+		Width:    f.Width,
+		Height:   f.Height,
+		Tracking: result,
+		Cursor:   cursor,
+	})
+	r.frames++
+	return err
+}
+
+// Command appends a command received by app.App. name identifies the
+// command; params is only set for parameter changes.
+func (r *Recorder) Command(name string, x, y int, enabled bool, params *config.Params) error {
+	return r.write(Record{
+		Type:    TypeCommand,
+		T:       time.Since(r.start),
+		Frame:   r.frames,
+		Command: name,
+		X:       x,
+		Y:       y,
+		Enabled: enabled,
+		Params:  params,
+	})
+}
+
+// Camera appends a camera state change such as a disconnect.
+func (r *Recorder) Camera(event string) error {
+	return r.write(Record{Type: TypeCamera, T: time.Since(r.start), Frame: r.frames, Event: event})
+}
+
+// Close finishes the current video segment and the record log.
+func (r *Recorder) Close() error {
+	var errs []error
+	if r.video != nil {
+		errs = append(errs, r.video.Close())
+		r.video = nil
+	}
+	errs = append(errs, r.events.Close())
+	return errors.Join(errs...)
+}
+
+func (r *Recorder) writeVideo(f camera.Frame) error {
+	if r.video == nil || f.Width != r.width || f.Height != r.height {
+		if r.video != nil {
+			if err := r.video.Close(); err != nil {
+				return err
+			}
+			r.video = nil
+		}
+		name := fmt.Sprintf("frames-%03d.avi", r.frames)
+		w, err := gocv.VideoWriterFile(filepath.Join(r.dir, name), videoCodec, videoFPS,
+			f.Width, f.Height, f.Mat.Channels() > 1)
+		if err != nil {
+			return err
+		}
+		if !w.IsOpened() {
+			w.Close()
+			return fmt.Errorf("recorder: cannot open %s for writing", name)
+		}
+		r.video = w
+		r.segment = name
+		r.width = f.Width
+		r.height = f.Height
+	}
+	return r.video.Write(f.Mat)
+}
+
+func (r *Recorder) write(rec Record) error {
+	return r.enc.Encode(rec)
+}
+
+// ReadRecords loads a session's events.jsonl.
+func ReadRecords(dir string) ([]Record, error) {
+	f, err := os.Open(filepath.Join(dir, EventsFile))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []Record
+	dec := json.NewDecoder(f)
+	for dec.More() {
+		var rec Record
+		if err := dec.Decode(&rec); err != nil {
+			return records, err
+		}
+		records = append(records, rec)
+	}
+	return records, nil
+}
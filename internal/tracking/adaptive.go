@@ -0,0 +1,60 @@
+package tracking
+
+import (
+	"image"
+
+	"gocv.io/x/gocv"
+)
+
+// anchorMinScore is how closely a patch must still resemble the template
+// picked by the user before it may be blended in. Every blended patch passes
+// this check, so the adapted template can't wander off onto the background
+// one confident-but-slightly-off match at a time.
+const anchorMinScore = 0.5
+
+// adapt blends the patch matched at rect into the template with weight
+// AdaptiveRate. The blend is accumulated in float so small rates still
+// register on 8-bit images. Patches matched at another scale or angle are
+// warped back to the template's frame first.
+func (t *TemplateTracker) adapt(gray gocv.Mat, m match) {
+	region := gray.Region(m.rect)
+	defer region.Close()
+	patch := region
+	if m.scale != 1 || m.angle != 0 {
+		size := image.Pt(t.template.Cols(), t.template.Rows())
+		patch = warp(region, size, -m.angle, 1/m.scale)
+		defer patch.Close()
+	}
+	if similarity(patch, t.anchor) < anchorMinScore {
+		return
+	}
+
+	patchF := gocv.NewMat()
+	defer patchF.Close()
+	patch.ConvertTo(&patchF, gocv.MatTypeCV32F)
+
+	rate := t.params.AdaptiveRate
+	gocv.AddWeighted(t.accum, 1-rate, patchF, rate, 0, &t.accum)
+	t.accum.ConvertTo(&t.template, gocv.MatTypeCV8U)
+}
+
+// resetAdaptive makes the freshly picked template the anchor and restarts
+// the blend from it.
+func (t *TemplateTracker) resetAdaptive() {
+	t.anchor.Close()
+	t.anchor = t.template.Clone()
+	t.template.ConvertTo(&t.accum, gocv.MatTypeCV32F)
+}
+
+// similarity is the normalized correlation of two equally sized patches.
+func similarity(a, b gocv.Mat) float64 {
+	if a.Rows() != b.Rows() || a.Cols() != b.Cols() {
+		return 0
+	}
+	res := gocv.NewMat()
+	defer res.Close()
+	mask := gocv.NewMat()
+	defer mask.Close()
+	gocv.MatchTemplate(a, b, &res, gocv.TmCcoeffNormed, mask)
+	return float64(res.GetFloatAt(0, 0))
+}
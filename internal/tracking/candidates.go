@@ -0,0 +1,183 @@
+package tracking
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"gocv.io/x/gocv"
+)
+
+const (
+	// scaleStep is how far the template scale may change between two
+	// frames; moving toward or away from the camera changes apparent size
+	// slowly.
+	scaleStep = 0.05
+	// rotationStepDeg is how far the template angle may change between two
+	// frames.
+	rotationStepDeg = 5.0
+)
+
+// candidate is one template transform tried in a frame: scale relative to
+// the picked patch and rotation in degrees, counterclockwise in the frame.
+type candidate struct {
+	scale float64
+	angle float64
+}
+
+// match is the best placement of one candidate in the search area.
+type match struct {
+	candidate
+	score       float64
+	sharpness   float64
+	secondRatio float64
+	rect        image.Rectangle
+	center      image.Point
+}
+
+// confidence copies the match's quality measures into r.
+func (m match) confidence(r Result) Result {
+	r.Score = m.score
+	r.Sharpness = m.sharpness
+	r.SecondBestRatio = m.secondRatio
+	return r
+}
+
+// scaleEnabled reports whether the configured range allows any scale other
+// than the picked one.
+func (t *TemplateTracker) scaleEnabled() bool {
+	return t.params.scaleMin() < 1 || t.params.scaleMax() > 1
+}
+
+// candidates are the transforms tried this frame: the last matched one,
+// then one scale step and one rotation step either side of it, each within
+// its configured range. Scale and angle are stepped separately so the cost
+// grows linearly, not with the product of both.
+func (t *TemplateTracker) candidates() []candidate {
+	cur := candidate{scale: t.scale, angle: t.angle}
+	out := []candidate{cur}
+	if t.scaleEnabled() {
+		if lo := math.Max(cur.scale/(1+scaleStep), t.params.scaleMin()); lo < cur.scale {
+			out = append(out, candidate{scale: lo, angle: cur.angle})
+		}
+		if hi := math.Min(cur.scale*(1+scaleStep), t.params.scaleMax()); hi > cur.scale {
+			out = append(out, candidate{scale: hi, angle: cur.angle})
+		}
+	}
+	if limit := t.params.RotationMaxDeg; limit > 0 {
+		if lo := math.Max(cur.angle-rotationStepDeg, -limit); lo < cur.angle {
+			out = append(out, candidate{scale: cur.scale, angle: lo})
+		}
+		if hi := math.Min(cur.angle+rotationStepDeg, limit); hi > cur.angle {
+			out = append(out, candidate{scale: cur.scale, angle: hi})
+		}
+	}
+	return out
+}
+
+// matchCandidate matches the template transformed by c inside searchRect.
+func (t *TemplateTracker) matchCandidate(gray gocv.Mat, searchRect image.Rectangle, c candidate) (match, bool) {
+	if c.scale == 1 && c.angle == 0 {
+		return matchIn(gray, searchRect, t.template, c)
+	}
+	size := image.Pt(
+		max(1, int(math.Round(float64(t.template.Cols())*c.scale))),
+		max(1, int(math.Round(float64(t.template.Rows())*c.scale))),
+	)
+	transformed := warp(t.template, size, c.angle, c.scale)
+	defer transformed.Close()
+	return matchIn(gray, searchRect, transformed, c)
+}
+
+// warp rotates src by angle degrees (counterclockwise) and scales it about
+// its center into a new Mat of the given size, centered. Corners uncovered
+// by the rotation repeat the nearest edge pixels, which disturbs the
+// correlation far less than a constant fill.
+func warp(src gocv.Mat, size image.Point, angle, scale float64) gocv.Mat {
+	dst := gocv.NewMat()
+	if angle == 0 {
+		gocv.Resize(src, &dst, size, 0, 0, gocv.InterpolationLinear)
+		return dst
+	}
+	rad := angle * math.Pi / 180
+	a, b := scale*math.Cos(rad), scale*math.Sin(rad)
+	sx, sy := float64(src.Cols()-1)/2, float64(src.Rows()-1)/2
+	dx, dy := float64(size.X-1)/2, float64(size.Y-1)/2
+
+	m := gocv.NewMatWithSize(2, 3, gocv.MatTypeCV64F)
+	defer m.Close()
+	m.SetDoubleAt(0, 0, a)
+	m.SetDoubleAt(0, 1, b)
+	m.SetDoubleAt(0, 2, dx-a*sx-b*sy)
+	m.SetDoubleAt(1, 0, -b)
+	m.SetDoubleAt(1, 1, a)
+	m.SetDoubleAt(1, 2, dy+b*sx-a*sy)
+	gocv.WarpAffineWithParams(src, &dst, m, size, gocv.InterpolationLinear, gocv.BorderReplicate, color.RGBA{})
+	return dst
+}
+
+// matchIn runs normalized cross-correlation of templ over searchRect of gray
+// and returns the peak. ok is false when templ doesn't fit.
+func matchIn(gray gocv.Mat, searchRect image.Rectangle, templ gocv.Mat, c candidate) (match, bool) {
+	resultCols := searchRect.Dx() - templ.Cols() + 1
+	resultRows := searchRect.Dy() - templ.Rows() + 1
+	if resultCols <= 0 || resultRows <= 0 {
+		return match{}, false
+	}
+
+	searchMat := gray.Region(searchRect)
+	defer searchMat.Close()
+
+	response := gocv.NewMatWithSize(resultRows, resultCols, gocv.MatTypeCV32F)
+	defer response.Close()
+	mask := gocv.NewMat()
+	defer mask.Close()
+
+	gocv.MatchTemplate(searchMat, templ, &response, gocv.TmCcoeffNormed, mask)
+
+	_, maxVal, _, maxLoc := gocv.MinMaxLoc(response)
+	topLeft := searchRect.Min.Add(maxLoc)
+	m := match{
+		candidate: c,
+		score:     float64(maxVal),
+		rect:      image.Rectangle{Min: topLeft, Max: topLeft.Add(image.Pt(templ.Cols(), templ.Rows()))},
+		center:    image.Pt(topLeft.X+templ.Cols()/2, topLeft.Y+templ.Rows()/2),
+	}
+	if data, err := response.DataPtrFloat32(); err == nil {
+		exclude := image.Rect(maxLoc.X-templ.Cols()/2, maxLoc.Y-templ.Rows()/2,
+			maxLoc.X+templ.Cols()/2+1, maxLoc.Y+templ.Rows()/2+1)
+		m.sharpness, m.secondRatio = peakStats(data, resultCols, maxVal, exclude)
+	}
+	return m, true
+}
+
+// peakStats measures how distinct the peak of a correlation map is, from the
+// responses outside exclude, the peak's own neighbourhood of about one
+// template size: the peak-to-sidelobe ratio (peak minus their mean, over
+// their standard deviation) and the largest of them relative to the peak.
+// Both are zero when too little of the map lies outside exclude.
+func peakStats(data []float32, cols int, peak float32, exclude image.Rectangle) (sharpness, secondRatio float64) {
+	var sum, sumSq float64
+	n := 0
+	second := float32(-1)
+	for i, v := range data {
+		if image.Pt(i%cols, i/cols).In(exclude) {
+			continue
+		}
+		sum += float64(v)
+		sumSq += float64(v) * float64(v)
+		n++
+		second = max(second, v)
+	}
+	if n < 2 {
+		return 0, 0
+	}
+	mean := sum / float64(n)
+	if std := math.Sqrt(math.Max(sumSq/float64(n)-mean*mean, 0)); std > 0 {
+		sharpness = (float64(peak) - mean) / std
+	}
+	if peak > 0 {
+		secondRatio = math.Max(float64(second), 0) / float64(peak)
+	}
+	return sharpness, secondRatio
+}
@@ -0,0 +1,158 @@
+package tracking
+
+import (
+	"image"
+	"math"
+	"testing"
+)
+
+func TestCandidates(t *testing.T) {
+	tests := []struct {
+		name         string
+		params       Params
+		scale, angle float64
+		want         []candidate
+	}{
+		{
+			name:  "zero range matches at the picked scale",
+			scale: 1,
+			want:  []candidate{{1, 0}},
+		},
+		{
+			name:   "scale steps both ways",
+			params: Params{ScaleMin: 0.8, ScaleMax: 1.25},
+			scale:  1,
+			want:   []candidate{{1, 0}, {1 / 1.05, 0}, {1.05, 0}},
+		},
+		{
+			name:   "scale stops at the maximum",
+			params: Params{ScaleMin: 0.8, ScaleMax: 1.25},
+			scale:  1.2,
+			want:   []candidate{{1.2, 0}, {1.2 / 1.05, 0}, {1.25, 0}},
+		},
+		{
+			name:   "scale at the minimum",
+			params: Params{ScaleMin: 0.8, ScaleMax: 1.25},
+			scale:  0.8,
+			want:   []candidate{{0.8, 0}, {0.84, 0}},
+		},
+		{
+			name:   "only larger",
+			params: Params{ScaleMax: 1.5},
+			scale:  1,
+			want:   []candidate{{1, 0}, {1.05, 0}},
+		},
+		{
+			name:   "rotation steps both ways",
+			params: Params{RotationMaxDeg: 20},
+			scale:  1,
+			want:   []candidate{{1, 0}, {1, -5}, {1, 5}},
+		},
+		{
+			name:   "rotation stops at the limit",
+			params: Params{RotationMaxDeg: 20},
+			scale:  1,
+			angle:  18,
+			want:   []candidate{{1, 18}, {1, 13}, {1, 20}},
+		},
+		{
+			name:   "rotation at the limit",
+			params: Params{RotationMaxDeg: 20},
+			scale:  1,
+			angle:  -20,
+			want:   []candidate{{1, -20}, {1, -15}},
+		},
+		{
+			name:   "scale and rotation stepped separately",
+			params: Params{ScaleMin: 0.8, ScaleMax: 1.25, RotationMaxDeg: 20},
+			scale:  1,
+			angle:  5,
+			want:   []candidate{{1, 5}, {1 / 1.05, 5}, {1.05, 5}, {1, 0}, {1, 10}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := &TemplateTracker{params: tt.params, scale: tt.scale, angle: tt.angle}
+			got := tracker.candidates()
+			if len(got) != len(tt.want) {
+				t.Fatalf("candidates = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if math.Abs(got[i].scale-tt.want[i].scale) > 1e-9 || math.Abs(got[i].angle-tt.want[i].angle) > 1e-9 {
+					t.Fatalf("candidates = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+// correlationMap is a 5×5 map with peak in the middle, the rest of the
+// central 3×3 at half of it, and side(x, y) outside it.
+func correlationMap(peak float32, side func(x, y int) float32) []float32 {
+	data := make([]float32, 25)
+	for i := range data {
+		x, y := i%5, i/5
+		switch {
+		case x == 2 && y == 2:
+			data[i] = peak
+		case x >= 1 && x <= 3 && y >= 1 && y <= 3:
+			data[i] = peak / 2
+		default:
+			data[i] = side(x, y)
+		}
+	}
+	return data
+}
+
+func TestPeakStats(t *testing.T) {
+	center := image.Rect(1, 1, 4, 4)
+	tests := []struct {
+		name        string
+		data        []float32
+		peak        float32
+		exclude     image.Rectangle
+		sharpness   float64
+		secondRatio float64
+	}{
+		{
+			name: "distinct peak",
+			data: correlationMap(1, func(x, y int) float32 {
+				return 0.2 * float32((x+y)%2)
+			}),
+			peak:        1,
+			exclude:     center,
+			sharpness:   9,
+			secondRatio: 0.2,
+		},
+		{
+			name:        "flat sidelobes",
+			data:        correlationMap(1, func(int, int) float32 { return 0.25 }),
+			peak:        1,
+			exclude:     center,
+			sharpness:   0,
+			secondRatio: 0.25,
+		},
+		{
+			name:        "negative peak",
+			data:        correlationMap(-0.125, func(int, int) float32 { return -0.25 }),
+			peak:        -0.125,
+			exclude:     center,
+			sharpness:   0,
+			secondRatio: 0,
+		},
+		{
+			name:    "nothing outside the peak",
+			data:    correlationMap(1, func(int, int) float32 { return 0.5 }),
+			peak:    1,
+			exclude: image.Rect(0, 0, 5, 5),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sharpness, second := peakStats(tt.data, 5, tt.peak, tt.exclude)
+			if math.Abs(sharpness-tt.sharpness) > 1e-3 || math.Abs(second-tt.secondRatio) > 1e-3 {
+				t.Errorf("peakStats = %.3f, %.3f, want %.3f, %.3f", sharpness, second, tt.sharpness, tt.secondRatio)
+			}
+		})
+	}
+}
@@ -0,0 +1,309 @@
+package tracking
+
+import (
+	"image"
+	"math"
+
+	"gocv.io/x/gocv"
+)
+
+// Patch states reported in Result.Patches.
+const (
+	PatchOK = "ok"
+	// PatchLost patches found no match above the threshold.
+	PatchLost = "lost"
+	// PatchOutlier patches matched somewhere that disagrees with the
+	// others, e.g. onto a hand covering them.
+	PatchOutlier = "outlier"
+)
+
+// constellationOffsets place the patches around the picked point, in
+// template sizes: the point itself (the nose tip), then between the eyes
+// and the two nostrils for a user who picked the nose.
+var constellationOffsets = [...][2]float64{
+	{0, 0},
+	{0, -1.5},
+	{-0.9, 0.6},
+	{0.9, 0.6},
+}
+
+const (
+	// consensusTolerance is how far, in template sizes, a patch's vote for
+	// the point may be from the median vote and still count (at least
+	// minConsensusTolerancePx).
+	consensusTolerance       = 0.25
+	minConsensusTolerancePx  = 3
+	minConsensusPatches      = 2
+	repickAfterFrames        = 10
+	anchorOnlyRepickMinScore = 0.8
+)
+
+// PatchHealth is one constellation patch's state in a frame.
+type PatchHealth struct {
+	X     int     `json:"x"`
+	Y     int     `json:"y"`
+	Score float64 `json:"score"`
+	State string  `json:"state"`
+}
+
+// ConstellationTracker follows several template patches around the picked
+// point, each with its own TemplateTracker. Every patch that matched votes
+// for where the point is — its position minus its offset, turned and scaled
+// like the constellation — and the point is the mean of the votes near
+// their median. Two agreeing patches are enough, so covering part of the
+// face doesn't lose the point; failing that, the picked patch alone is
+// followed as a plain template tracker would. Patches that stay lost or
+// out of line are re-picked where they should be once the constellation
+// is confidently located.
+type ConstellationTracker struct {
+	params      Params
+	patches     []*constellationPatch
+	point       image.Point
+	scale       float64
+	angle       float64
+	hasTemplate bool
+}
+
+type constellationPatch struct {
+	tracker *TemplateTracker
+	// offset from the point at baseScale and baseAngle, the constellation's
+	// scale and angle when the patch was picked; the patch's own Scale and
+	// Angle are relative to that.
+	offset    [2]float64
+	baseScale float64
+	baseAngle float64
+	// unhealthy counts consecutive frames lost or out of line.
+	unhealthy int
+}
+
+// vote is where a patch puts the point, with the constellation scale and
+// angle it implies.
+type vote struct {
+	x, y         float64
+	scale, angle float64
+}
+
+func NewConstellation(params Params) *ConstellationTracker {
+	return &ConstellationTracker{params: params, scale: 1}
+}
+
+func (t *ConstellationTracker) SetParams(params Params) {
+	t.params = params
+	for i, p := range t.patches {
+		p.tracker.SetParams(t.patchParams(i))
+	}
+}
+
+func (t *ConstellationTracker) HasTemplate() bool {
+	return t.hasTemplate
+}
+
+// Pick picks the point itself as the anchor patch and the other patches at
+// their offsets. Only the anchor has to succeed; patches that would fall
+// outside the frame are pushed inside it and their offsets measured from
+// where they ended up.
+func (t *ConstellationTracker) Pick(frame gocv.Mat, x, y int) error {
+	anchor := NewTemplate(t.patchParams(0))
+	if err := anchor.Pick(frame, x, y); err != nil {
+		anchor.Close()
+		return err
+	}
+	t.closePatches()
+	t.point = anchor.templatePoint
+	t.scale, t.angle = 1, 0
+	t.patches = []*constellationPatch{{tracker: anchor, baseScale: 1}}
+
+	size := float64(t.params.TemplateSizePx)
+	for i, off := range constellationOffsets[1:] {
+		p := NewTemplate(t.patchParams(i + 1))
+		px := t.point.X + int(math.Round(off[0]*size))
+		py := t.point.Y + int(math.Round(off[1]*size))
+		if err := p.Pick(frame, px, py); err != nil {
+			p.Close()
+			continue
+		}
+		t.patches = append(t.patches, &constellationPatch{
+			tracker:   p,
+			offset:    t.offsetOf(p),
+			baseScale: 1,
+		})
+	}
+	t.hasTemplate = true
+	return nil
+}
+
+func (t *ConstellationTracker) Update(frame gocv.Mat) Result {
+	if !t.hasTemplate {
+		return Result{Lost: true}
+	}
+
+	results := make([]Result, len(t.patches))
+	votes := make([]*vote, len(t.patches))
+	for i, p := range t.patches {
+		results[i] = p.tracker.Update(frame)
+		if !results[i].Lost {
+			votes[i] = p.vote(results[i])
+		}
+	}
+
+	inliers := consensus(votes, t.tolerance())
+	health := make([]PatchHealth, len(t.patches))
+	for i, r := range results {
+		health[i] = PatchHealth{X: r.X, Y: r.Y, Score: r.Score, State: PatchOK}
+		switch {
+		case r.Lost:
+			health[i].State = PatchLost
+		case !inliers[i]:
+			health[i].State = PatchOutlier
+		}
+	}
+
+	res, confident, ok := t.locate(results, votes, inliers)
+	if !ok {
+		anchor := results[0]
+		return Result{
+			Lost: true, X: t.point.X, Y: t.point.Y, Score: anchor.Score,
+			Scale: t.scale, Angle: t.angle, Reacquiring: anchor.Reacquiring, Patches: health,
+		}
+	}
+	t.point = image.Pt(res.X, res.Y)
+	t.scale, t.angle = res.Scale, res.Angle
+	t.repick(frame, health, confident)
+	res.Patches = health
+	return res
+}
+
+func (t *ConstellationTracker) Close() {
+	t.closePatches()
+}
+
+// locate combines the patches' votes into the point. confident is set when
+// the result is trustworthy enough to re-pick drifted patches against: a
+// majority of patches agree, or the anchor alone matched strongly.
+func (t *ConstellationTracker) locate(results []Result, votes []*vote, inliers []bool) (res Result, confident, ok bool) {
+	var agreeing []*vote
+	var score, sharpness, second float64
+	for i, in := range inliers {
+		if in {
+			agreeing = append(agreeing, votes[i])
+			score += results[i].Score
+			sharpness += results[i].Sharpness
+			second += results[i].SecondBestRatio
+		}
+	}
+
+	if len(agreeing) >= minConsensusPatches {
+		n := float64(len(agreeing))
+		var x, y float64
+		scales, angles := make([]float64, 0, len(agreeing)), make([]float64, 0, len(agreeing))
+		for _, v := range agreeing {
+			x += v.x
+			y += v.y
+			scales = append(scales, v.scale)
+			angles = append(angles, v.angle)
+		}
+		res = Result{
+			X: int(math.Round(x / n)), Y: int(math.Round(y / n)),
+			Score: score / n, Sharpness: sharpness / n, SecondBestRatio: second / n,
+			Scale: median(scales), Angle: median(angles),
+		}
+		return res, 2*len(agreeing) > len(t.patches), true
+	}
+
+	if anchor := results[0]; !anchor.Lost {
+		v := votes[0]
+		res = anchor
+		res.Scale, res.Angle = v.scale, v.angle
+		return res, anchor.Score >= anchorOnlyRepickMinScore, true
+	}
+	return Result{}, false, false
+}
+
+// repick re-picks patches that have been lost or out of line for a while at
+// the position the located constellation says they should be at.
+func (t *ConstellationTracker) repick(frame gocv.Mat, health []PatchHealth, confident bool) {
+	for i, p := range t.patches {
+		if health[i].State == PatchOK {
+			p.unhealthy = 0
+			continue
+		}
+		p.unhealthy++
+		if !confident || p.unhealthy < repickAfterFrames {
+			continue
+		}
+		ox, oy := turn(p.offset, t.scale/p.baseScale, t.angle-p.baseAngle)
+		if p.tracker.Pick(frame, t.point.X+int(math.Round(ox)), t.point.Y+int(math.Round(oy))) != nil {
+			continue
+		}
+		p.offset = t.offsetOf(p.tracker)
+		p.baseScale, p.baseAngle = t.scale, t.angle
+		p.unhealthy = 0
+	}
+}
+
+// patchParams are the params for patch i. Only the anchor searches the whole
+// frame once lost; a stray patch doing so would just find lookalikes.
+func (t *ConstellationTracker) patchParams(i int) Params {
+	params := t.params
+	if i > 0 {
+		params.ReacquireAfterFrames = 0
+	}
+	return params
+}
+
+// offsetOf is where patch p was picked relative to the point.
+func (t *ConstellationTracker) offsetOf(p *TemplateTracker) [2]float64 {
+	d := p.templatePoint.Sub(t.point)
+	return [2]float64{float64(d.X), float64(d.Y)}
+}
+
+func (t *ConstellationTracker) tolerance() float64 {
+	return math.Max(consensusTolerance*float64(t.params.TemplateSizePx), minConsensusTolerancePx)
+}
+
+func (t *ConstellationTracker) closePatches() {
+	for _, p := range t.patches {
+		p.tracker.Close()
+	}
+	t.patches = nil
+	t.hasTemplate = false
+}
+
+// vote derives the point from the patch's match: the patch's offset, turned
+// and scaled as the patch has been since it was picked, taken back off its
+// position.
+func (p *constellationPatch) vote(r Result) *vote {
+	scale := p.baseScale * r.Scale
+	angle := p.baseAngle + r.Angle
+	ox, oy := turn(p.offset, r.Scale, r.Angle)
+	return &vote{x: float64(r.X) - ox, y: float64(r.Y) - oy, scale: scale, angle: angle}
+}
+
+// consensus marks the votes within tol of their median.
+func consensus(votes []*vote, tol float64) []bool {
+	var xs, ys []float64
+	for _, v := range votes {
+		if v != nil {
+			xs = append(xs, v.x)
+			ys = append(ys, v.y)
+		}
+	}
+	inliers := make([]bool, len(votes))
+	if len(xs) == 0 {
+		return inliers
+	}
+	mx, my := median(xs), median(ys)
+	for i, v := range votes {
+		inliers[i] = v != nil && math.Hypot(v.x-mx, v.y-my) <= tol
+	}
+	return inliers
+}
+
+// turn scales an offset and rotates it by angle degrees counterclockwise in
+// the frame (whose y axis points down).
+func turn(off [2]float64, scale, angle float64) (float64, float64) {
+	rad := angle * math.Pi / 180
+	cos, sin := math.Cos(rad), math.Sin(rad)
+	x, y := off[0]*scale, off[1]*scale
+	return x*cos + y*sin, -x*sin + y*cos
+}
@@ -0,0 +1,87 @@
+package tracking
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"open-camera-mouse/internal/camera"
+
+	"gocv.io/x/gocv"
+)
+
+func TestConsensus(t *testing.T) {
+	tests := []struct {
+		name  string
+		votes []*vote
+		want  []bool
+	}{
+		{
+			name:  "all agree",
+			votes: []*vote{{x: 100, y: 100}, {x: 101, y: 99}, {x: 99, y: 100}},
+			want:  []bool{true, true, true},
+		},
+		{
+			name:  "one out of line",
+			votes: []*vote{{x: 100, y: 100}, {x: 101, y: 100}, {x: 140, y: 100}, {x: 100, y: 101}},
+			want:  []bool{true, true, false, true},
+		},
+		{
+			name:  "lost patches don't vote",
+			votes: []*vote{{x: 100, y: 100}, nil, {x: 102, y: 100}, nil},
+			want:  []bool{true, false, true, false},
+		},
+		{
+			name:  "no votes",
+			votes: []*vote{nil, nil},
+			want:  []bool{false, false},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := consensus(tt.votes, 6)
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Fatalf("consensus = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+// TestConstellationCoveredPatch covers the patch between the eyes for a
+// while, as a hand or the glasses' bridge might: the others keep the point.
+func TestConstellationCoveredPatch(t *testing.T) {
+	const coverFrom, coverTo = 20, 60
+	size := constellationParams().TemplateSizePx
+	offset := image.Pt(0, int(constellationOffsets[1][1]*float64(size)))
+	cover := func(frame *gocv.Mat, i int, truth camera.Truth) {
+		if i < coverFrom || i >= coverTo {
+			return
+		}
+		c := image.Pt(truth.X, truth.Y).Add(offset)
+		r := image.Rect(c.X-size*2/3, c.Y-size*2/3, c.X+size*2/3, c.Y+size*2/3)
+		gocv.Rectangle(frame, r, color.RGBA{R: 96, G: 96, B: 96}, -1)
+	}
+
+	tracker := New(constellationParams())
+	defer tracker.Close()
+	steps := runSynthetic(t, tracker, constellationOptions(wanderPath), cover)
+
+	maxErr, lost := trackingError(steps)
+	if lost > 0 {
+		t.Errorf("lost the point in %d frames", lost)
+	}
+	if maxErr > 1 {
+		t.Errorf("max error %.1f px, want at most 1", maxErr)
+	}
+	covered := 0
+	for _, s := range steps[coverFrom:coverTo] {
+		if len(s.res.Patches) > 1 && s.res.Patches[1].State != PatchOK {
+			covered++
+		}
+	}
+	if covered == 0 {
+		t.Error("the covered patch was never reported lost or out of line")
+	}
+}
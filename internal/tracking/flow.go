@@ -0,0 +1,227 @@
+package tracking
+
+import (
+	"errors"
+	"image"
+	"math"
+	"sort"
+
+	"gocv.io/x/gocv"
+)
+
+const (
+	maxFlowFeatures = 40
+	// flowFeatureQuality is low so that faces without much texture still
+	// yield enough corners; forward-backward checking weeds out the weak
+	// ones that don't track.
+	flowFeatureQuality = 0.005
+	flowFeatureMinDist = 3
+	// minFlowFeatures is the fewest surviving features the point is still
+	// trusted with.
+	minFlowFeatures = 5
+	// flowBackErrorPx is how far a feature tracked forward and then back
+	// may land from where it started.
+	flowBackErrorPx = 1.0
+	// flowDeviationPx drops features moving differently from the cluster,
+	// such as ones on the background at the patch edge.
+	flowDeviationPx = 2.0
+	// flowReseedFraction refills the cluster around the tracked point once
+	// fewer than this share of the seeded features survive.
+	flowReseedFraction = 0.5
+	flowWindowPx       = 15
+	flowPyramidLevels  = 3
+)
+
+var errTooFewFeatures = errors.New("tracking: too few features to follow at pick point")
+
+// FlowTracker follows a cluster of corner features around the picked point
+// with pyramidal Lucas-Kanade optical flow. Each feature is tracked forward
+// and back again and kept only if it returns to where it started; the point
+// moves by the median displacement of the survivors. It copes with faces
+// that have too little texture for a stable correlation peak.
+type FlowTracker struct {
+	params      Params
+	prev        gocv.Mat
+	points      []gocv.Point2f
+	seeded      int
+	x, y        float64
+	hasTemplate bool
+}
+
+func NewFlow(params Params) *FlowTracker {
+	return &FlowTracker{params: params, prev: gocv.NewMat()}
+}
+
+func (t *FlowTracker) SetParams(params Params) {
+	t.params = params
+}
+
+func (t *FlowTracker) HasTemplate() bool {
+	return t.hasTemplate
+}
+
+// Pick seeds the cluster with features within a templateSizePx square
+// around (x, y), clamped into the frame like the template tracker's pick.
+func (t *FlowTracker) Pick(frame gocv.Mat, x, y int) error {
+	size := t.params.TemplateSizePx
+	if size <= 0 {
+		return errInvalidPick
+	}
+
+	gray := toGray(frame)
+	half := size / 2
+	center := image.Pt(clampCenter(x, half, gray.Cols()), clampCenter(y, half, gray.Rows()))
+	if !t.seed(gray, center) {
+		gray.Close()
+		return errTooFewFeatures
+	}
+
+	t.prev.Close()
+	t.prev = gray
+	t.x, t.y = float64(center.X), float64(center.Y)
+	t.hasTemplate = true
+	return nil
+}
+
+func (t *FlowTracker) Update(frame gocv.Mat) Result {
+	if !t.hasTemplate {
+		return Result{Lost: true}
+	}
+
+	gray := toGray(frame)
+	defer func() {
+		t.prev.Close()
+		t.prev = gray
+	}()
+
+	lost := Result{Lost: true, X: int(math.Round(t.x)), Y: int(math.Round(t.y)), Scale: 1}
+	if len(t.points) < minFlowFeatures {
+		// Lost last frame: start a fresh cluster where the point was, to
+		// be followed from the next frame on.
+		t.seed(gray, lost.point())
+		return lost
+	}
+
+	tracked := len(t.points)
+	dx, dy, ok := t.flow(gray)
+	// Score is the share of features that survived, not a correlation.
+	lost.Score = float64(len(t.points)) / float64(tracked)
+	if !ok {
+		t.points = nil
+		return lost
+	}
+
+	t.x = clampF(t.x+dx, 0, float64(gray.Cols()-1))
+	t.y = clampF(t.y+dy, 0, float64(gray.Rows()-1))
+	res := Result{X: int(math.Round(t.x)), Y: int(math.Round(t.y)), Score: lost.Score, Scale: 1}
+	if float64(len(t.points)) < float64(t.seeded)*flowReseedFraction {
+		t.seed(gray, res.point())
+	}
+	return res
+}
+
+func (t *FlowTracker) Close() {
+	t.prev.Close()
+}
+
+// flow tracks the cluster from t.prev into gray, keeps the features that
+// pass the forward-backward check and agree with the median motion, and
+// returns that motion. ok is false when too few features survive.
+func (t *FlowTracker) flow(gray gocv.Mat) (dx, dy float64, ok bool) {
+	prevPts := pointsMat(t.points)
+	defer prevPts.Close()
+	next, back := gocv.NewMat(), gocv.NewMat()
+	defer next.Close()
+	defer back.Close()
+	status, backStatus, errs := gocv.NewMat(), gocv.NewMat(), gocv.NewMat()
+	defer status.Close()
+	defer backStatus.Close()
+	defer errs.Close()
+
+	criteria := gocv.NewTermCriteria(gocv.Count|gocv.EPS, 20, 0.03)
+	win := image.Pt(flowWindowPx, flowWindowPx)
+	gocv.CalcOpticalFlowPyrLKWithParams(t.prev, gray, prevPts, next, &status, &errs,
+		win, flowPyramidLevels, criteria, 0, 1e-4)
+	gocv.CalcOpticalFlowPyrLKWithParams(gray, t.prev, next, back, &backStatus, &errs,
+		win, flowPyramidLevels, criteria, 0, 1e-4)
+
+	var moved []gocv.Point2f
+	var dxs, dys []float64
+	for i, p := range t.points {
+		if status.GetUCharAt(i, 0) == 0 || backStatus.GetUCharAt(i, 0) == 0 {
+			continue
+		}
+		n, b := next.GetVecfAt(i, 0), back.GetVecfAt(i, 0)
+		if math.Hypot(float64(b[0]-p.X), float64(b[1]-p.Y)) > flowBackErrorPx {
+			continue
+		}
+		moved = append(moved, gocv.Point2f{X: n[0], Y: n[1]})
+		dxs = append(dxs, float64(n[0]-p.X))
+		dys = append(dys, float64(n[1]-p.Y))
+	}
+	if len(moved) < minFlowFeatures {
+		t.points = moved
+		return 0, 0, false
+	}
+
+	dx, dy = median(dxs), median(dys)
+	t.points = t.points[:0]
+	for i, p := range moved {
+		if math.Hypot(dxs[i]-dx, dys[i]-dy) <= flowDeviationPx {
+			t.points = append(t.points, p)
+		}
+	}
+	return dx, dy, len(t.points) >= minFlowFeatures
+}
+
+// seed replaces the cluster with the strongest corners in the
+// templateSizePx square around center. It leaves the cluster alone and
+// returns false if the patch has too few of them.
+func (t *FlowTracker) seed(gray gocv.Mat, center image.Point) bool {
+	size := t.params.TemplateSizePx
+	half := size / 2
+	rect := image.Rect(center.X-half, center.Y-half, center.X-half+size, center.Y-half+size).
+		Intersect(image.Rect(0, 0, gray.Cols(), gray.Rows()))
+	if rect.Empty() {
+		return false
+	}
+
+	roi := gray.Region(rect)
+	defer roi.Close()
+	corners := gocv.NewMat()
+	defer corners.Close()
+	gocv.GoodFeaturesToTrack(roi, &corners, maxFlowFeatures, flowFeatureQuality, flowFeatureMinDist)
+	if corners.Rows() < minFlowFeatures {
+		return false
+	}
+
+	points := make([]gocv.Point2f, corners.Rows())
+	for i := range points {
+		v := corners.GetVecfAt(i, 0)
+		points[i] = gocv.Point2f{X: v[0] + float32(rect.Min.X), Y: v[1] + float32(rect.Min.Y)}
+	}
+	t.points = points
+	t.seeded = len(points)
+	return true
+}
+
+// pointsMat packs points into the Nx1 two-channel float Mat optical flow
+// takes.
+func pointsMat(points []gocv.Point2f) gocv.Mat {
+	v := gocv.NewPoint2fVectorFromPoints(points)
+	defer v.Close()
+	return gocv.NewMatFromPoint2fVector(v, true)
+}
+
+func median(v []float64) float64 {
+	s := append([]float64(nil), v...)
+	sort.Float64s(s)
+	if n := len(s); n%2 == 0 {
+		return (s[n/2-1] + s[n/2]) / 2
+	}
+	return s[len(s)/2]
+}
+
+func clampF(v, lo, hi float64) float64 {
+	return math.Max(lo, math.Min(v, hi))
+}
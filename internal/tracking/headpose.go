@@ -0,0 +1,173 @@
+package tracking
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"image"
+	"io/fs"
+	"math"
+	"os"
+
+	"gocv.io/x/gocv"
+)
+
+// headPoseFlags is OpenCV's SOLVEPNP_SQPNP, which gocv doesn't name. Unlike
+// the default iterative solver it needs no initial guess from six or more
+// points; the five landmarks are enough.
+const headPoseFlags = 8
+
+// headModel is a generic adult face in millimetres, in the order YuNet
+// reports landmarks: right eye, left eye, nose tip, right and left mouth
+// corner (the subject's right and left). Axes follow the camera's — x to
+// the right of an unmirrored frame, y down, z away from the camera — with
+// the nose tip at the origin, so a head facing the camera has no rotation.
+var headModel = []gocv.Point3f{
+	{X: -31.5, Y: -35, Z: 30},
+	{X: 31.5, Y: -35, Z: 30},
+	{X: 0, Y: 0, Z: 0},
+	{X: -25, Y: 32, Z: 22},
+	{X: 25, Y: 32, Z: 22},
+}
+
+// yunetModelFile is OpenCV's YuNet face detection model, embedded when
+// ci/fetch-models.sh has put it in models/ before the build.
+const yunetModelFile = "models/face_detection_yunet_2023mar.onnx"
+
+//go:embed models
+var models embed.FS
+
+// yunetLandmarks is the column of the first landmark's x in a YuNet
+// detection row, after the box's x, y, width and height.
+const yunetLandmarks = 4
+
+var errHeadPoseModel = errors.New("tracking: head pose model not found")
+
+// Pose is the head's orientation in degrees relative to facing the camera.
+// Yaw is positive when the nose turns toward the right of the unmirrored
+// frame, Pitch when it tilts up, and Roll when the head tilts
+// counterclockwise in the frame, like Result.Angle.
+type Pose struct {
+	Yaw   float64 `json:"yaw"`
+	Pitch float64 `json:"pitch"`
+	Roll  float64 `json:"roll"`
+}
+
+// HeadPoseEstimator estimates the head's orientation from five facial
+// landmarks found by OpenCV's YuNet face detector, fitting headModel to them
+// with SolvePnP. The camera is assumed to be an undistorted pinhole with a
+// focal length of about the frame width, which is close enough for webcams;
+// the absolute angles carry a small constant bias that calibration absorbs.
+// It is not safe for concurrent use.
+type HeadPoseEstimator struct {
+	detector gocv.FaceDetectorYN
+	size     image.Point
+}
+
+// NewHeadPoseEstimator loads the YuNet model (face_detection_yunet_*.onnx)
+// from modelPath, or the embedded one if modelPath is empty. Unlike the
+// face package's cascade, YuNet loads straight from memory.
+func NewHeadPoseEstimator(modelPath string) (*HeadPoseEstimator, error) {
+	size := image.Pt(320, 320)
+	if modelPath == "" {
+		data, err := models.ReadFile(yunetModelFile)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errHeadPoseModel, err)
+		}
+		return &HeadPoseEstimator{detector: gocv.NewFaceDetectorYNFromBytes("onnx", data, nil, size)}, nil
+	}
+	if _, err := os.Stat(modelPath); err != nil {
+		return nil, fmt.Errorf("%w: %w", errHeadPoseModel, err)
+	}
+	return &HeadPoseEstimator{detector: gocv.NewFaceDetectorYN(modelPath, "", size)}, nil
+}
+
+// HeadPoseAvailable reports whether NewHeadPoseEstimator(modelPath) would
+// find a model to load.
+func HeadPoseAvailable(modelPath string) bool {
+	var err error
+	if modelPath == "" {
+		_, err = fs.Stat(models, yunetModelFile)
+	} else {
+		_, err = os.Stat(modelPath)
+	}
+	return err == nil
+}
+
+// Estimate returns the pose of the most confident face in frame, which must
+// be the unprocessed color frame YuNet was trained on.
+func (e *HeadPoseEstimator) Estimate(frame gocv.Mat) (Pose, bool) {
+	size := image.Pt(frame.Cols(), frame.Rows())
+	if size != e.size {
+		e.detector.SetInputSize(size)
+		e.size = size
+	}
+
+	img := frame
+	if frame.Channels() == 1 {
+		img = gocv.NewMat()
+		defer img.Close()
+		gocv.CvtColor(frame, &img, gocv.ColorGrayToBGR)
+	}
+	faces := gocv.NewMat()
+	defer faces.Close()
+	e.detector.Detect(img, &faces)
+	if faces.Rows() == 0 {
+		return Pose{}, false
+	}
+
+	// Rows come sorted by score.
+	landmarks := make([]gocv.Point2f, len(headModel))
+	for i := range landmarks {
+		landmarks[i] = gocv.Point2f{
+			X: faces.GetFloatAt(0, yunetLandmarks+2*i),
+			Y: faces.GetFloatAt(0, yunetLandmarks+2*i+1),
+		}
+	}
+	return solveHeadPose(landmarks, size)
+}
+
+func (e *HeadPoseEstimator) Close() {
+	e.detector.Close()
+}
+
+// solveHeadPose fits headModel to the landmarks and decomposes the rotation
+// as R = Rz(roll)·Ry(yaw)·Rx(pitch) in the camera's axes, flipping signs to
+// Pose's conventions.
+func solveHeadPose(landmarks []gocv.Point2f, size image.Point) (Pose, bool) {
+	objectPts := gocv.NewPoint3fVectorFromPoints(headModel)
+	defer objectPts.Close()
+	imagePts := gocv.NewPoint2fVectorFromPoints(landmarks)
+	defer imagePts.Close()
+
+	focal := float64(size.X)
+	camera := gocv.NewMatWithSize(3, 3, gocv.MatTypeCV64F)
+	defer camera.Close()
+	camera.SetDoubleAt(0, 0, focal)
+	camera.SetDoubleAt(1, 1, focal)
+	camera.SetDoubleAt(0, 2, float64(size.X)/2)
+	camera.SetDoubleAt(1, 2, float64(size.Y)/2)
+	camera.SetDoubleAt(2, 2, 1)
+	dist := gocv.NewMat()
+	defer dist.Close()
+
+	rvec, tvec := gocv.NewMat(), gocv.NewMat()
+	defer rvec.Close()
+	defer tvec.Close()
+	if !gocv.SolvePnP(objectPts, imagePts, camera, dist, &rvec, &tvec, false, headPoseFlags) {
+		return Pose{}, false
+	}
+	rot := gocv.NewMat()
+	defer rot.Close()
+	if gocv.Rodrigues(rvec, &rot) != nil || rot.Rows() != 3 || rot.Cols() != 3 {
+		return Pose{}, false
+	}
+
+	r := func(i, j int) float64 { return rot.GetDoubleAt(i, j) }
+	const deg = 180 / math.Pi
+	return Pose{
+		Yaw:   -math.Atan2(-r(2, 0), math.Hypot(r(2, 1), r(2, 2))) * deg,
+		Pitch: -math.Atan2(r(2, 1), r(2, 2)) * deg,
+		Roll:  -math.Atan2(r(1, 0), r(0, 0)) * deg,
+	}, true
+}
@@ -0,0 +1,120 @@
+package tracking
+
+import (
+	"image"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gocv.io/x/gocv"
+)
+
+type mat3 [3][3]float64
+
+func (a mat3) mul(b mat3) mat3 {
+	var c mat3
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			for k := 0; k < 3; k++ {
+				c[i][j] += a[i][k] * b[k][j]
+			}
+		}
+	}
+	return c
+}
+
+// projectHead projects headModel turned to pose, 600 mm in front of the
+// pinhole camera solveHeadPose assumes for a frame of size: the landmarks a
+// perfect detector would report. The rotation is Rz·Ry·Rx with the signs
+// of Pose's conventions undone.
+func projectHead(pose Pose, size image.Point) []gocv.Point2f {
+	const rad = math.Pi / 180
+	y, p, r := -pose.Yaw*rad, -pose.Pitch*rad, -pose.Roll*rad
+	rz := mat3{{math.Cos(r), -math.Sin(r), 0}, {math.Sin(r), math.Cos(r), 0}, {0, 0, 1}}
+	ry := mat3{{math.Cos(y), 0, math.Sin(y)}, {0, 1, 0}, {-math.Sin(y), 0, math.Cos(y)}}
+	rx := mat3{{1, 0, 0}, {0, math.Cos(p), -math.Sin(p)}, {0, math.Sin(p), math.Cos(p)}}
+	rot := rz.mul(ry).mul(rx)
+
+	focal := float64(size.X)
+	out := make([]gocv.Point2f, len(headModel))
+	for i, m := range headModel {
+		v := [3]float64{float64(m.X), float64(m.Y), float64(m.Z)}
+		var c [3]float64
+		for j := range c {
+			c[j] = rot[j][0]*v[0] + rot[j][1]*v[1] + rot[j][2]*v[2]
+		}
+		c[2] += 600
+		out[i] = gocv.Point2f{
+			X: float32(focal*c[0]/c[2] + float64(size.X)/2),
+			Y: float32(focal*c[1]/c[2] + float64(size.Y)/2),
+		}
+	}
+	return out
+}
+
+func TestSolveHeadPose(t *testing.T) {
+	size := image.Pt(640, 480)
+	tests := []struct {
+		name string
+		pose Pose
+	}{
+		{"facing the camera", Pose{}},
+		{"turned right", Pose{Yaw: 15}},
+		{"turned left", Pose{Yaw: -25}},
+		{"tilted up", Pose{Pitch: 10}},
+		{"tilted down", Pose{Pitch: -12}},
+		{"rolled", Pose{Roll: 20}},
+		{"all at once", Pose{Yaw: 10, Pitch: -8, Roll: 5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := solveHeadPose(projectHead(tt.pose, size), size)
+			if !ok {
+				t.Fatal("no pose")
+			}
+			if math.Abs(got.Yaw-tt.pose.Yaw) > 0.5 || math.Abs(got.Pitch-tt.pose.Pitch) > 0.5 || math.Abs(got.Roll-tt.pose.Roll) > 0.5 {
+				t.Errorf("pose %+v, want %+v", got, tt.pose)
+			}
+		})
+	}
+}
+
+// TestProjectHeadConventions pins the test's projection to Pose's
+// documented conventions, so a sign flip in both can't go unnoticed.
+func TestProjectHeadConventions(t *testing.T) {
+	size := image.Pt(640, 480)
+	const rightEye, leftEye, nose = 0, 1, 2
+	facing := projectHead(Pose{}, size)
+
+	// Turning right brings the far (left) eye toward the nose in the
+	// frame, as the nose swings right of the eyes.
+	right := projectHead(Pose{Yaw: 15}, size)
+	if !(right[nose].X-right[leftEye].X > facing[nose].X-facing[leftEye].X) {
+		t.Errorf("yaw 15: nose %.1f not further right of the left eye %.1f than when facing", right[nose].X, right[leftEye].X)
+	}
+	// Tilting up moves the eyes, behind the nose tip, down relative to it.
+	up := projectHead(Pose{Pitch: 10}, size)
+	if !(up[rightEye].Y-up[nose].Y > facing[rightEye].Y-facing[nose].Y) {
+		t.Errorf("pitch 10: eyes not lower relative to the nose than when facing")
+	}
+	// A counterclockwise roll lifts the subject's left eye, on the frame's
+	// right, above the right eye.
+	rolled := projectHead(Pose{Roll: 20}, size)
+	if !(rolled[leftEye].Y < rolled[rightEye].Y) {
+		t.Errorf("roll 20: left eye at y %.1f, right eye at y %.1f; want the left one higher", rolled[leftEye].Y, rolled[rightEye].Y)
+	}
+}
+
+func TestHeadPoseAvailableOverride(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "yunet.onnx")
+	if HeadPoseAvailable(path) {
+		t.Errorf("HeadPoseAvailable(%q) = true before the file exists", path)
+	}
+	if err := os.WriteFile(path, []byte("model"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if !HeadPoseAvailable(path) {
+		t.Errorf("HeadPoseAvailable(%q) = false, want true", path)
+	}
+}
@@ -0,0 +1,306 @@
+package tracking
+
+import (
+	"errors"
+	"image"
+	"math"
+
+	"gocv.io/x/gocv"
+)
+
+const (
+	// markerCorePx is the side of the square around the pick point whose
+	// color is learned. It is small because markers are: the user clicks
+	// on the sticker, and medians shrug off the background at its corners.
+	markerCorePx = 7
+	// markerSpread is how many robust standard deviations of the picked
+	// color the segmentation accepts either way, within the tolerances
+	// below (hue in OpenCV's 0–180 units, saturation and value in 0–255).
+	markerSpread    = 3.0
+	minHueTolerance = 6.0
+	maxHueTolerance = 25.0
+	minSatTolerance = 40.0
+	minValTolerance = 60.0
+	// minMarkerSaturation separates colored stickers from white or
+	// reflective dots, which are segmented on brightness alone: their hue
+	// is noise.
+	minMarkerSaturation = 60.0
+	hueCircle           = 180.0
+	// minMarkerAreaPx ignores specks of the color. markerAreaRatio is how
+	// much larger or smaller than in the previous frame a blob may be and
+	// still be taken for the marker.
+	minMarkerAreaPx = 4
+	markerAreaRatio = 5.0
+	// maxPickFill rejects a pick whose color fills more than this share of
+	// the pick square: that is background or skin, not a marker.
+	maxPickFill = 0.5
+)
+
+var (
+	errNoColor  = errors.New("tracking: marker tracking needs a color camera image")
+	errNoMarker = errors.New("tracking: no distinct marker of the picked color at pick point")
+)
+
+// Point is a position in the frame with sub-pixel precision.
+type Point struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// MarkerTracker follows a colored sticker or reflective dot worn on the
+// forehead or glasses. Pick learns the marker's color in HSV from the
+// pixels around the pick point, with a tolerance from their spread; Update
+// segments that color in a window around the last position and follows the
+// centroid of the nearest blob of about the marker's size, to sub-pixel
+// precision. It works on the camera's color frame, so preprocessing does
+// not apply.
+type MarkerTracker struct {
+	params   Params
+	color    hsvRange
+	x, y     float64
+	area     float64
+	pickArea float64
+	// lostFrames counts consecutive frames without the marker.
+	lostFrames  int
+	hasTemplate bool
+	hsv         gocv.Mat
+	mask        gocv.Mat
+	kernel      gocv.Mat
+}
+
+// hsvRange is the color a MarkerTracker segments. Hue may extend past
+// either end of the hue circle, wrapping around red.
+type hsvRange struct {
+	hueLo, hueHi float64
+	satLo, satHi float64
+	valLo, valHi float64
+}
+
+// blob is a connected region of the marker color.
+type blob struct {
+	x, y float64
+	area float64
+}
+
+func NewMarker(params Params) *MarkerTracker {
+	return &MarkerTracker{
+		params: params,
+		hsv:    gocv.NewMat(),
+		mask:   gocv.NewMat(),
+		kernel: gocv.GetStructuringElement(gocv.MorphEllipse, image.Pt(3, 3)),
+	}
+}
+
+func (t *MarkerTracker) SetParams(params Params) {
+	t.params = params
+}
+
+func (t *MarkerTracker) HasTemplate() bool {
+	return t.hasTemplate
+}
+
+// Pick learns the color at (x, y) and locks onto the blob of it nearest
+// that point within a templateSizePx square.
+func (t *MarkerTracker) Pick(frame gocv.Mat, x, y int) error {
+	size := t.params.TemplateSizePx
+	if size <= 0 {
+		return errInvalidPick
+	}
+	if frame.Channels() < 3 {
+		return errNoColor
+	}
+
+	bounds := image.Rect(0, 0, frame.Cols(), frame.Rows())
+	pt := image.Pt(clamp(x, 0, frame.Cols()-1), clamp(y, 0, frame.Rows()-1))
+	half := markerCorePx / 2
+	core := image.Rect(pt.X-half, pt.Y-half, pt.X-half+markerCorePx, pt.Y-half+markerCorePx).Intersect(bounds)
+	color, ok := learnColor(frame, core)
+	if !ok {
+		return errInvalidPick
+	}
+
+	rect := computeSearchRect(frame, pt, size/2)
+	prev := t.color
+	t.color = color
+	b, found := t.findBlob(frame, rect, float64(pt.X), float64(pt.Y), 0)
+	if !found || b.area > maxPickFill*float64(rect.Dx()*rect.Dy()) {
+		t.color = prev
+		return errNoMarker
+	}
+
+	t.x, t.y = b.x, b.y
+	t.area, t.pickArea = b.area, b.area
+	t.lostFrames = 0
+	t.hasTemplate = true
+	return nil
+}
+
+func (t *MarkerTracker) Update(frame gocv.Mat) Result {
+	if !t.hasTemplate {
+		return Result{Lost: true}
+	}
+	lost := Result{Lost: true, X: int(math.Round(t.x)), Y: int(math.Round(t.y)), Scale: t.scale(t.area)}
+	if frame.Channels() < 3 {
+		t.lostFrames++
+		return lost
+	}
+
+	// Long lost, the whole frame is searched: a marker is distinct enough
+	// that no coarse pass is needed first.
+	rect := image.Rect(0, 0, frame.Cols(), frame.Rows())
+	n := t.params.ReacquireAfterFrames
+	lost.Reacquiring = n > 0 && t.lostFrames >= n
+	if !lost.Reacquiring {
+		margin := int(math.Round(float64(t.params.TemplateSizePx) * t.params.searchMargin()))
+		rect = computeSearchRect(frame, lost.point(), margin)
+	}
+	b, found := t.findBlob(frame, rect, t.x, t.y, t.area)
+	if !found {
+		t.lostFrames++
+		return lost
+	}
+
+	score := math.Min(b.area, t.area) / math.Max(b.area, t.area)
+	t.x, t.y, t.area = b.x, b.y, b.area
+	t.lostFrames = 0
+	return Result{
+		X: int(math.Round(b.x)), Y: int(math.Round(b.y)), Score: score, Scale: t.scale(b.area),
+		Precise: &Point{X: b.x, Y: b.y},
+	}
+}
+
+func (t *MarkerTracker) Close() {
+	t.hsv.Close()
+	t.mask.Close()
+	t.kernel.Close()
+}
+
+// scale is a blob's size relative to the picked marker.
+func (t *MarkerTracker) scale(area float64) float64 {
+	if t.pickArea <= 0 {
+		return 1
+	}
+	return math.Sqrt(area / t.pickArea)
+}
+
+// findBlob segments t.color within rect and returns the blob whose
+// centroid is nearest (nearX, nearY). With area set, blobs more than
+// markerAreaRatio times larger or smaller are skipped.
+func (t *MarkerTracker) findBlob(frame gocv.Mat, rect image.Rectangle, nearX, nearY, area float64) (blob, bool) {
+	if rect.Empty() {
+		return blob{}, false
+	}
+	roi := frame.Region(rect)
+	defer roi.Close()
+	gocv.CvtColor(roi, &t.hsv, gocv.ColorBGRToHSV)
+	t.color.segment(t.hsv, &t.mask)
+	gocv.MorphologyEx(t.mask, &t.mask, gocv.MorphOpen, t.kernel)
+
+	labels, stats, centroids := gocv.NewMat(), gocv.NewMat(), gocv.NewMat()
+	defer labels.Close()
+	defer stats.Close()
+	defer centroids.Close()
+	n := gocv.ConnectedComponentsWithStats(t.mask, &labels, &stats, &centroids)
+
+	var best blob
+	found, bestDist := false, 0.0
+	// Label 0 is the background.
+	for i := 1; i < n; i++ {
+		a := float64(stats.GetIntAt(i, int(gocv.CC_STAT_AREA)))
+		if a < minMarkerAreaPx || (area > 0 && (a > area*markerAreaRatio || a < area/markerAreaRatio)) {
+			continue
+		}
+		b := blob{
+			x:    centroids.GetDoubleAt(i, 0) + float64(rect.Min.X),
+			y:    centroids.GetDoubleAt(i, 1) + float64(rect.Min.Y),
+			area: a,
+		}
+		if d := math.Hypot(b.x-nearX, b.y-nearY); !found || d < bestDist {
+			best, found, bestDist = b, true, d
+		}
+	}
+	return best, found
+}
+
+// learnColor measures the color in rect: the median hue, saturation and
+// value, each accepted within markerSpread robust standard deviations.
+// Hues are taken relative to the center pixel's so red, which straddles
+// the ends of the hue circle, averages correctly.
+func learnColor(frame gocv.Mat, rect image.Rectangle) (hsvRange, bool) {
+	if rect.Empty() {
+		return hsvRange{}, false
+	}
+	roi := frame.Region(rect)
+	defer roi.Close()
+	hsv := gocv.NewMat()
+	defer hsv.Close()
+	gocv.CvtColor(roi, &hsv, gocv.ColorBGRToHSV)
+	px, err := hsv.DataPtrUint8()
+	if err != nil {
+		return hsvRange{}, false
+	}
+
+	center := 3 * (hsv.Rows()/2*hsv.Cols() + hsv.Cols()/2)
+	ref := float64(px[center])
+	hues := make([]float64, 0, len(px)/3)
+	sats := make([]float64, 0, len(px)/3)
+	vals := make([]float64, 0, len(px)/3)
+	for i := 0; i+2 < len(px); i += 3 {
+		hues = append(hues, wrapHue(float64(px[i])-ref))
+		sats = append(sats, float64(px[i+1]))
+		vals = append(vals, float64(px[i+2]))
+	}
+
+	hue, hueDev := medianDev(hues)
+	sat, satDev := medianDev(sats)
+	val, valDev := medianDev(vals)
+	satTol := math.Max(markerSpread*satDev, minSatTolerance)
+	valTol := math.Max(markerSpread*valDev, minValTolerance)
+	r := hsvRange{valLo: val - valTol, valHi: 255}
+	if sat < minMarkerSaturation {
+		r.hueLo, r.hueHi = 0, hueCircle-1
+		r.satLo, r.satHi = 0, sat+satTol
+		return r, true
+	}
+	hueTol := clampF(markerSpread*hueDev, minHueTolerance, maxHueTolerance)
+	hue += ref
+	r.hueLo, r.hueHi = hue-hueTol, hue+hueTol
+	r.satLo, r.satHi = sat-satTol, 255
+	return r, true
+}
+
+// segment marks the pixels of hsv within r in mask.
+func (r hsvRange) segment(hsv gocv.Mat, mask *gocv.Mat) {
+	lo := gocv.NewScalar(math.Max(r.hueLo, 0), r.satLo, r.valLo, 0)
+	hi := gocv.NewScalar(math.Min(r.hueHi, hueCircle-1), r.satHi, r.valHi, 0)
+	gocv.InRangeWithScalar(hsv, lo, hi, mask)
+
+	switch {
+	case r.hueLo < 0:
+		lo.Val1, hi.Val1 = r.hueLo+hueCircle, hueCircle-1
+	case r.hueHi > hueCircle-1:
+		lo.Val1, hi.Val1 = 0, r.hueHi-hueCircle
+	default:
+		return
+	}
+	wrapped := gocv.NewMat()
+	defer wrapped.Close()
+	gocv.InRangeWithScalar(hsv, lo, hi, &wrapped)
+	gocv.BitwiseOr(*mask, wrapped, mask)
+}
+
+// wrapHue brings a hue difference into [-90, 90).
+func wrapHue(d float64) float64 {
+	return math.Mod(math.Mod(d+hueCircle/2, hueCircle)+hueCircle, hueCircle) - hueCircle/2
+}
+
+// medianDev returns the median of v and its median absolute deviation,
+// scaled to estimate a standard deviation.
+func medianDev(v []float64) (float64, float64) {
+	m := median(v)
+	dev := make([]float64, len(v))
+	for i, x := range v {
+		dev[i] = math.Abs(x - m)
+	}
+	return m, 1.4826 * median(dev)
+}
@@ -0,0 +1,111 @@
+package tracking
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+
+	"open-camera-mouse/internal/camera"
+
+	"gocv.io/x/gocv"
+)
+
+// sticker draws a red dot, whose hue straddles the ends of the hue circle,
+// on a plain patch over the synthetic target, like a sticker on the
+// forehead.
+func sticker(frame *gocv.Mat, i int, truth camera.Truth) {
+	c := image.Pt(truth.X, truth.Y)
+	gocv.Rectangle(frame, image.Rect(c.X-24, c.Y-24, c.X+24, c.Y+24), color.RGBA{R: 150, G: 130, B: 120}, -1)
+	gocv.Circle(frame, c, 6, color.RGBA{R: 220, G: 20, B: 30}, -1)
+}
+
+func TestWrapHue(t *testing.T) {
+	tests := []struct {
+		d, want float64
+	}{
+		{0, 0},
+		{10, 10},
+		{-10, -10},
+		{170, -10},
+		{-170, 10},
+		{89, 89},
+		{90, -90},
+		{-90, -90},
+		{180, 0},
+		{-360, 0},
+	}
+	for _, tt := range tests {
+		if got := wrapHue(tt.d); got != tt.want {
+			t.Errorf("wrapHue(%v) = %v, want %v", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestMedianDev(t *testing.T) {
+	tests := []struct {
+		name     string
+		v        []float64
+		med, dev float64
+	}{
+		{"constant", []float64{7, 7, 7}, 7, 0},
+		{"outlier ignored", []float64{1, 2, 3, 4, 100}, 3, 1.4826},
+		{"even count", []float64{1, 2, 4, 5}, 3, 1.5 * 1.4826},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			med, dev := medianDev(tt.v)
+			if math.Abs(med-tt.med) > 1e-9 || math.Abs(dev-tt.dev) > 1e-9 {
+				t.Errorf("medianDev = %v, %v, want %v, %v", med, dev, tt.med, tt.dev)
+			}
+		})
+	}
+}
+
+func TestSegmentWrapsHue(t *testing.T) {
+	// One pixel per hue, fully saturated and bright.
+	hues := []uint8{176, 179, 0, 4, 12, 90, 170}
+	data := make([]byte, 0, 3*len(hues))
+	for _, h := range hues {
+		data = append(data, h, 255, 255)
+	}
+	hsv, err := gocv.NewMatFromBytes(1, len(hues), gocv.MatTypeCV8UC3, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer hsv.Close()
+	mask := gocv.NewMat()
+	defer mask.Close()
+
+	tests := []struct {
+		name string
+		r    hsvRange
+		want []bool
+	}{
+		{
+			name: "below zero",
+			r:    hsvRange{hueLo: -6, hueHi: 6, satLo: 100, satHi: 255, valLo: 100, valHi: 255},
+			want: []bool{true, true, true, true, false, false, false},
+		},
+		{
+			name: "past the top",
+			r:    hsvRange{hueLo: 172, hueHi: 184, satLo: 100, satHi: 255, valLo: 100, valHi: 255},
+			want: []bool{true, true, true, true, false, false, false},
+		},
+		{
+			name: "no wrap",
+			r:    hsvRange{hueLo: 84, hueHi: 96, satLo: 100, satHi: 255, valLo: 100, valHi: 255},
+			want: []bool{false, false, false, false, false, true, false},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.r.segment(hsv, &mask)
+			for i, want := range tt.want {
+				if got := mask.GetUCharAt(0, i) != 0; got != want {
+					t.Errorf("hue %d: in range = %v, want %v", hues[i], got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package tracking
+
+import (
+	"image"
+	"math"
+)
+
+// Constant-velocity alpha-beta filter gains. Matches are precise, so the
+// filter mostly follows the measurement; it exists to place and size the
+// search window, not to smooth the output.
+const (
+	predictAlpha = 0.8
+	predictBeta  = 0.3
+	// residualDecay weights the running mean of prediction errors.
+	residualDecay = 0.7
+	// coastDecay damps the velocity for every frame without a match, so a
+	// prediction drifts to a stop instead of running off.
+	coastDecay = 0.8
+	// maxCoastFrames is how long a predicted point is reported while lost.
+	maxCoastFrames = 5
+)
+
+// predictor tracks position and velocity (px per frame) of the target.
+type predictor struct {
+	x, y     float64
+	vx, vy   float64
+	residual float64
+	missed   int
+	// px, py is the prediction for the current frame, set by predict.
+	px, py float64
+}
+
+func (p *predictor) reset(pt image.Point) {
+	*p = predictor{x: float64(pt.X), y: float64(pt.Y), px: float64(pt.X), py: float64(pt.Y)}
+}
+
+// predict advances the filter to the current frame and returns where the
+// target is expected.
+func (p *predictor) predict() image.Point {
+	p.px = p.x + p.vx
+	p.py = p.y + p.vy
+	return image.Pt(int(math.Round(p.px)), int(math.Round(p.py)))
+}
+
+// margin sizes the search window around the prediction from how fast the
+// target moves, how wrong recent predictions were and how many frames have
+// gone unmatched, between 1.5 and 4 template sizes.
+func (p *predictor) margin(templateSize int) int {
+	base := float64(templateSize) * 1.5
+	speed := math.Hypot(p.vx, p.vy)
+	m := base + 2*speed + 3*p.residual + float64(p.missed*templateSize)/2
+	return int(math.Min(m, float64(templateSize*4)))
+}
+
+// correct folds in the matched position for the current frame.
+func (p *predictor) correct(pt image.Point) {
+	rx, ry := float64(pt.X)-p.px, float64(pt.Y)-p.py
+	p.x = p.px + predictAlpha*rx
+	p.y = p.py + predictAlpha*ry
+	p.vx += predictBeta * rx
+	p.vy += predictBeta * ry
+	p.residual = residualDecay*p.residual + (1-residualDecay)*math.Hypot(rx, ry)
+	p.missed = 0
+}
+
+// miss advances without a match: the target is assumed to have kept moving,
+// slowing down. ok is false once it has been lost too long to trust the
+// returned point.
+func (p *predictor) miss() (image.Point, bool) {
+	p.x, p.y = p.px, p.py
+	p.vx *= coastDecay
+	p.vy *= coastDecay
+	p.missed++
+	return image.Pt(int(math.Round(p.x)), int(math.Round(p.y))), p.missed <= maxCoastFrames
+}
@@ -0,0 +1,89 @@
+package tracking
+
+import (
+	"image"
+	"math"
+	"testing"
+
+	"open-camera-mouse/internal/camera"
+)
+
+// fastPath speeds up from 20 to 40 px per frame, stops and turns back
+// sharply: more than a one-template-size window keeps up with.
+var fastPath = []camera.PathSegment{
+	{Kind: camera.SegmentLine, Frames: 8, To: image.Pt(220, 240)},
+	{Kind: camera.SegmentLine, Frames: 9, To: image.Pt(580, 240)},
+	{Kind: camera.SegmentHold, Frames: 5},
+	{Kind: camera.SegmentLine, Frames: 9, To: image.Pt(220, 240)},
+}
+
+func TestPredictorConstantVelocity(t *testing.T) {
+	var p predictor
+	p.reset(image.Pt(0, 0))
+	for k := 1; k <= 20; k++ {
+		p.predict()
+		p.correct(image.Pt(10*k, -5*k))
+	}
+	if got, want := p.predict(), image.Pt(210, -105); math.Hypot(float64(got.X-want.X), float64(got.Y-want.Y)) > 1 {
+		t.Errorf("predicted %v, want %v", got, want)
+	}
+}
+
+func TestPredictorCoasts(t *testing.T) {
+	var p predictor
+	p.reset(image.Pt(0, 0))
+	for k := 1; k <= 20; k++ {
+		p.predict()
+		p.correct(image.Pt(10*k, 0))
+	}
+
+	last, step := 200, 10
+	for i := 1; i <= maxCoastFrames+1; i++ {
+		p.predict()
+		pt, ok := p.miss()
+		if want := i <= maxCoastFrames; ok != want {
+			t.Errorf("miss %d: ok = %v, want %v", i, ok, want)
+		}
+		if d := pt.X - last; d <= 0 || d > step {
+			t.Errorf("miss %d: moved %d px after %d, want it to keep going and slow down", i, d, step)
+		} else {
+			step = d
+		}
+		last = pt.X
+	}
+}
+
+func TestPredictorMargin(t *testing.T) {
+	var p predictor
+	p.reset(image.Pt(100, 100))
+	if got := p.margin(48); got != 72 {
+		t.Errorf("margin at rest = %d, want 72", got)
+	}
+	p.vx = 1000
+	if got := p.margin(48); got != 4*48 {
+		t.Errorf("margin when fast = %d, want %d", got, 4*48)
+	}
+}
+
+func TestPredictiveFollowsFastTarget(t *testing.T) {
+	opts := camera.SyntheticOptions{Start: image.Pt(60, 240), Path: fastPath, Seed: 1}
+	params := templateParams()
+	params.SearchMargin = 1
+
+	plain := NewTemplate(params)
+	defer plain.Close()
+	if _, lost := trackingError(runSynthetic(t, plain, opts, nil)); lost == 0 {
+		t.Error("fixed window kept up with the fast target; the test path is too slow")
+	}
+
+	params.Predictive = true
+	predictive := NewTemplate(params)
+	defer predictive.Close()
+	maxErr, lost := trackingError(runSynthetic(t, predictive, opts, nil))
+	if lost > 0 {
+		t.Errorf("lost the target in %d frames", lost)
+	}
+	if maxErr > 1 {
+		t.Errorf("max error %.1f px, want at most 1", maxErr)
+	}
+}
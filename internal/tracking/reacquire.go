@@ -0,0 +1,89 @@
+package tracking
+
+import (
+	"image"
+	"math"
+
+	"gocv.io/x/gocv"
+)
+
+const (
+	// reacquireThreshold is the score a full-frame match needs to re-lock,
+	// unless Params.ScoreThreshold is stricter still. A search over the
+	// whole frame has far more chances to find a lookalike.
+	reacquireThreshold = 0.8
+	// maxPyramidLevels bounds how often the frame is halved for the coarse
+	// search; minCoarseTemplatePx stops earlier if the template would get
+	// too small to carry any texture.
+	maxPyramidLevels    = 3
+	minCoarseTemplatePx = 12
+)
+
+// reacquiring reports whether the target has been lost long enough to
+// search the whole frame instead of the window around its last position.
+func (t *TemplateTracker) reacquiring() bool {
+	n := t.params.ReacquireAfterFrames
+	return n > 0 && t.lostFrames >= n
+}
+
+// reacquire looks for the template anywhere in gray: a coarse match on a
+// downscaled pyramid level finds the most likely spot, then the usual
+// candidates are matched at full resolution in a window around it. The
+// tracker re-locks only if that refined match clears reacquireThreshold.
+func (t *TemplateTracker) reacquire(gray gocv.Mat, fallback Result) Result {
+	fallback.Reacquiring = true
+
+	coarse, ok := t.coarseMatch(gray)
+	if !ok {
+		return t.lost(fallback)
+	}
+	searchRect := computeSearchRect(gray, coarse, t.params.TemplateSizePx)
+	best, found := t.bestMatch(gray, searchRect)
+	if !found {
+		return t.lost(fallback)
+	}
+	if best.score < math.Max(reacquireThreshold, t.scoreThreshold()) {
+		return t.lost(best.confidence(fallback))
+	}
+
+	// Whatever motion the predictor had is long stale; start it afresh.
+	t.lock(best)
+	t.predictor.reset(best.center)
+	return best.confidence(Result{X: best.center.X, Y: best.center.Y, Scale: best.scale, Angle: best.angle})
+}
+
+// coarseMatch matches the template, at its last scale and angle, against a
+// halved-down copy of the whole frame and returns the peak's center in
+// full-resolution coordinates.
+func (t *TemplateTracker) coarseMatch(gray gocv.Mat) (image.Point, bool) {
+	cur := candidate{scale: t.scale, angle: t.angle}
+	templ := t.template.Clone()
+	if cur.scale != 1 || cur.angle != 0 {
+		templ.Close()
+		size := image.Pt(
+			max(1, int(math.Round(float64(t.template.Cols())*cur.scale))),
+			max(1, int(math.Round(float64(t.template.Rows())*cur.scale))),
+		)
+		templ = warp(t.template, size, cur.angle, cur.scale)
+	}
+	frame := gray.Clone()
+
+	levels := 0
+	for levels < maxPyramidLevels && min(templ.Cols(), templ.Rows())/2 >= minCoarseTemplatePx {
+		smallFrame, smallTempl := gocv.NewMat(), gocv.NewMat()
+		gocv.PyrDown(frame, &smallFrame, image.Point{}, gocv.BorderDefault)
+		gocv.PyrDown(templ, &smallTempl, image.Point{}, gocv.BorderDefault)
+		frame.Close()
+		templ.Close()
+		frame, templ = smallFrame, smallTempl
+		levels++
+	}
+	defer frame.Close()
+	defer templ.Close()
+
+	m, ok := matchIn(frame, image.Rect(0, 0, frame.Cols(), frame.Rows()), templ, cur)
+	if !ok {
+		return image.Point{}, false
+	}
+	return image.Pt(m.center.X<<levels, m.center.Y<<levels), true
+}
@@ -0,0 +1,69 @@
+package tracking
+
+import (
+	"image"
+	"testing"
+
+	"open-camera-mouse/internal/camera"
+)
+
+// jumpPath hides the target while it moves across the frame, far beyond
+// the search window around where it was last seen, and shows it again.
+var jumpPath = []camera.PathSegment{
+	{Kind: camera.SegmentHold, Frames: 5},
+	{Kind: camera.SegmentLine, Frames: 20, To: image.Pt(480, 360), Occluded: true},
+	{Kind: camera.SegmentHold, Frames: 30},
+}
+
+func TestReacquire(t *testing.T) {
+	const reacquireAfter = 5
+	opts := camera.SyntheticOptions{Start: image.Pt(160, 120), Path: jumpPath, Seed: 1}
+
+	params := templateParams()
+	params.ReacquireAfterFrames = reacquireAfter
+	tracker := NewTemplate(params)
+	defer tracker.Close()
+	steps := runSynthetic(t, tracker, opts, nil)
+
+	reappeared, searched := -1, false
+	for i, s := range steps {
+		if !s.truth.Visible {
+			if !s.res.Lost {
+				t.Errorf("frame %d: locked onto (%d, %d) while the target was hidden", i, s.res.X, s.res.Y)
+			}
+			searched = searched || s.res.Reacquiring
+			continue
+		}
+		if reappeared < 0 && i > 0 && !steps[i-1].truth.Visible {
+			reappeared = i
+		}
+	}
+	if reappeared < 0 {
+		t.Fatal("the target never reappeared")
+	}
+	if !searched {
+		t.Errorf("no full-frame search after %d lost frames", reacquireAfter)
+	}
+
+	// The full-frame search runs on every lost frame by now, so the target
+	// is found as soon as it shows.
+	maxErr, lost := trackingError(steps[reappeared:])
+	if lost > 0 {
+		t.Errorf("lost in %d frames after the target reappeared", lost)
+	}
+	if maxErr > 1 {
+		t.Errorf("max error %.1f px after reacquiring, want at most 1", maxErr)
+	}
+}
+
+func TestNoReacquireStaysLost(t *testing.T) {
+	opts := camera.SyntheticOptions{Start: image.Pt(160, 120), Path: jumpPath, Seed: 1}
+	tracker := NewTemplate(templateParams())
+	defer tracker.Close()
+	steps := runSynthetic(t, tracker, opts, nil)
+
+	last := steps[len(steps)-1].res
+	if !last.Lost || last.Reacquiring {
+		t.Errorf("last result %+v, want lost without a full-frame search", last)
+	}
+}
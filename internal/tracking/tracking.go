@@ -3,46 +3,184 @@ package tracking
 import (
 	"errors"
 	"image"
+	"math"
 
 	"gocv.io/x/gocv"
 )
 
+// Used when Params leaves ScoreThreshold or SearchMargin at zero.
 const (
-	searchMarginMultiplier = 2
-	scoreThreshold         = 0.68
+	defaultSearchMargin   = 2
+	defaultScoreThreshold = 0.68
+)
+
+// Tracker kinds accepted in Params.Kind.
+const (
+	KindTemplate = "template"
+	KindFlow     = "flow"
+	// KindConstellation tracks several template patches around the point.
+	KindConstellation = "constellation"
+	// KindMarker follows a colored sticker on the user's face or glasses.
+	KindMarker = "marker"
 )
 
 var errInvalidPick = errors.New("tracking: invalid pick point")
 
+// Tracker follows a point picked in a frame through the frames after it.
+// Implementations are not safe for concurrent use; app.App only calls them
+// from its run goroutine.
+type Tracker interface {
+	// Pick starts following (x, y) in frame.
+	Pick(frame gocv.Mat, x, y int) error
+	// Update locates the picked point in the next frame.
+	Update(frame gocv.Mat) Result
+	// HasTemplate reports whether a point has been picked.
+	HasTemplate() bool
+	SetParams(params Params)
+	Close()
+}
+
+// New returns the tracker selected by params.Kind; anything unknown gets
+// the template tracker.
+func New(params Params) Tracker {
+	switch params.Kind {
+	case KindFlow:
+		return NewFlow(params)
+	case KindConstellation:
+		return NewConstellation(params)
+	case KindMarker:
+		return NewMarker(params)
+	}
+	return NewTemplate(params)
+}
+
 type Params struct {
+	// Kind selects the Tracker New builds. It is not consulted afterwards;
+	// switching kinds means building a new tracker.
+	Kind string
+	// TemplateSizePx is the side of the picked patch; the flow tracker
+	// looks for features within a patch of this size, the marker tracker
+	// for the marker. The remaining fields only apply to the template
+	// tracker, but for SearchMargin and ReacquireAfterFrames, which the
+	// marker tracker honors too.
 	TemplateSizePx int
+	// ScoreThreshold is the lowest match score accepted as the target by the
+	// template and constellation trackers.
+	// SearchMargin is how far around the last position it is looked for,
+	// in template sizes; Predictive sizes its window itself.
+	ScoreThreshold float64
+	SearchMargin   float64
+	// AdaptiveTemplate lets the template follow slow appearance changes
+	// (lighting, head rotation) by blending in confidently matched patches,
+	// anchored to the originally picked patch. AdaptiveRate is the weight of
+	// each new patch (0–1); only matches scoring at least AdaptiveMinScore
+	// are blended.
+	AdaptiveTemplate bool
+	AdaptiveRate     float64
+	AdaptiveMinScore float64
+	// ScaleMin and ScaleMax bound the template scale, relative to the picked
+	// patch, as the user leans toward or away from the camera. 1 and 1 match
+	// at the picked size only; zero values mean 1.
+	ScaleMin float64
+	ScaleMax float64
+	// RotationMaxDeg lets the template follow head tilt up to this many
+	// degrees either way; 0 matches upright only.
+	RotationMaxDeg float64
+	// Predictive centers the search window on a constant-velocity
+	// prediction, sizes it by the prediction's uncertainty and reports the
+	// predicted point for a few frames after the target is lost.
+	Predictive bool
+	// ReacquireAfterFrames switches to a coarse search of the whole frame
+	// once the target has been lost for this many consecutive frames; 0
+	// keeps searching around the last position only.
+	ReacquireAfterFrames int
 }
 
 type Result struct {
-	Lost bool
-	X    int
-	Y    int
+	Lost bool `json:"lost"`
+	X    int  `json:"x"`
+	Y    int  `json:"y"`
+	// Score is how sure the tracker is of the point, from 0 to 1, also
+	// reported when it fell short; zero if no match was attempted. What it
+	// measures depends on the tracker: for the template and constellation
+	// trackers it is the normalized correlation of the best match (averaged
+	// over the patches), the only kind Params.ScoreThreshold applies to; for
+	// the flow tracker the share of features that survived the frame; for
+	// the marker tracker how closely the blob's area matches the last one.
+	Score float64 `json:"score"`
+	// Sharpness is how far the match peak stands out of the rest of the
+	// correlation map, in standard deviations; a flat, ambiguous map gives
+	// a low value. SecondBestRatio is the best score away from the peak
+	// relative to the peak: near 1 means a lookalike is competing.
+	Sharpness       float64 `json:"sharpness"`
+	SecondBestRatio float64 `json:"secondBestRatio"`
+	// Scale is the template scale of the match relative to the picked patch.
+	Scale float64 `json:"scale"`
+	// Angle is the estimated roll of the tracked patch since it was picked,
+	// in degrees, counterclockwise in the (unmirrored) frame.
+	Angle float64 `json:"angle"`
+	// Predicted is set on a Lost result whose X/Y is where the target is
+	// expected to be rather than where it was last seen. Only reported for
+	// a few frames with Params.Predictive.
+	Predicted bool `json:"predicted"`
+	// Reacquiring is set on a Lost result from a full-frame search, after
+	// Params.ReacquireAfterFrames lost frames.
+	Reacquiring bool `json:"reacquiring"`
+	// Patches is the state of each patch of a constellation, the picked
+	// one first; nil for the other trackers.
+	Patches []PatchHealth `json:"patches,omitempty"`
+	// Precise is X and Y to sub-pixel precision, from trackers that measure
+	// it (the marker tracker's blob centroid); nil for the others.
+	Precise *Point `json:"precise,omitempty"`
+	// Pose is the head's orientation, estimated alongside the tracker in
+	// absolute pointing mode; nil otherwise or when no face was found.
+	Pose *Pose `json:"pose,omitempty"`
 }
 
-type Tracker struct {
+func (r Result) point() image.Point {
+	return image.Pt(r.X, r.Y)
+}
+
+// Position is the point as precisely as the tracker located it.
+func (r Result) Position() (x, y float64) {
+	if r.Precise != nil {
+		return r.Precise.X, r.Precise.Y
+	}
+	return float64(r.X), float64(r.Y)
+}
+
+// TemplateTracker follows the picked patch by normalized cross-correlation
+// in a window around its last position.
+type TemplateTracker struct {
 	params        Params
 	template      gocv.Mat
 	templatePoint image.Point
 	hasTemplate   bool
+	scale         float64
+	angle         float64
+	predictor     predictor
+	lostFrames    int
+
+	// anchor is the patch as picked; accum is the adaptive blend in float,
+	// which template is converted from.
+	anchor gocv.Mat
+	accum  gocv.Mat
 }
 
-func New(params Params) *Tracker {
-	return &Tracker{
+func NewTemplate(params Params) *TemplateTracker {
+	return &TemplateTracker{
 		params:   params,
 		template: gocv.NewMat(),
+		anchor:   gocv.NewMat(),
+		accum:    gocv.NewMat(),
 	}
 }
 
-func (t *Tracker) SetParams(params Params) {
+func (t *TemplateTracker) SetParams(params Params) {
 	t.params = params
 }
 
-func (t *Tracker) HasTemplate() bool {
+func (t *TemplateTracker) HasTemplate() bool {
 	return t.hasTemplate
 }
 
@@ -50,7 +188,7 @@ func (t *Tracker) HasTemplate() bool {
 // template. The center is clamped so the full template fits inside the
 // frame; if the frame is smaller than the template, the template is cropped
 // to the frame bounds instead of failing.
-func (t *Tracker) Pick(frame gocv.Mat, x, y int) error {
+func (t *TemplateTracker) Pick(frame gocv.Mat, x, y int) error {
 	size := t.params.TemplateSizePx
 	if size <= 0 {
 		return errInvalidPick
@@ -73,14 +211,19 @@ func (t *Tracker) Pick(frame gocv.Mat, x, y int) error {
 	t.template.Close()
 	t.template = roi.Clone()
 	roi.Close()
+	t.resetAdaptive()
 
 	t.templatePoint = image.Point{X: cx, Y: cy}
 	t.hasTemplate = true
+	t.scale = 1
+	t.angle = 0
+	t.predictor.reset(t.templatePoint)
+	t.lostFrames = 0
 	return nil
 }
 
-func (t *Tracker) Update(frame gocv.Mat) Result {
-	fallback := Result{Lost: true, X: t.templatePoint.X, Y: t.templatePoint.Y}
+func (t *TemplateTracker) Update(frame gocv.Mat) Result {
+	fallback := Result{Lost: true, X: t.templatePoint.X, Y: t.templatePoint.Y, Scale: t.scale, Angle: t.angle}
 
 	if !t.hasTemplate || t.template.Empty() {
 		return Result{Lost: true}
@@ -89,48 +232,108 @@ func (t *Tracker) Update(frame gocv.Mat) Result {
 	gray := toGray(frame)
 	defer gray.Close()
 
-	margin := t.params.TemplateSizePx * searchMarginMultiplier
-	searchRect := computeSearchRect(gray, t.templatePoint, margin)
+	if t.reacquiring() {
+		return t.reacquire(gray, fallback)
+	}
+
+	// The predictor is kept up to date even when unused, so turning
+	// Predictive on mid-session starts from a sensible state.
+	center := t.templatePoint
+	margin := int(math.Round(float64(t.params.TemplateSizePx) * t.searchMargin()))
+	if predicted := t.predictor.predict(); t.params.Predictive {
+		center = predicted
+		margin = t.predictor.margin(t.params.TemplateSizePx)
+	}
+	searchRect := computeSearchRect(gray, center, margin)
 	if searchRect.Empty() {
-		return fallback
+		return t.lost(fallback)
 	}
 
-	resultCols := searchRect.Dx() - t.template.Cols() + 1
-	resultRows := searchRect.Dy() - t.template.Rows() + 1
-	if resultCols <= 0 || resultRows <= 0 {
-		return fallback
+	best, found := t.bestMatch(gray, searchRect)
+	if !found {
+		return t.lost(fallback)
+	}
+	if best.score < t.scoreThreshold() {
+		return t.lost(best.confidence(fallback))
 	}
 
-	searchMat := gray.Region(searchRect)
-	defer searchMat.Close()
+	t.lock(best)
+	t.predictor.correct(best.center)
+	if t.params.AdaptiveTemplate && best.score >= t.params.AdaptiveMinScore {
+		t.adapt(gray, best)
+	}
+
+	return best.confidence(Result{X: best.center.X, Y: best.center.Y, Scale: best.scale, Angle: best.angle})
+}
+
+func (t *TemplateTracker) scoreThreshold() float64 {
+	if t.params.ScoreThreshold > 0 {
+		return t.params.ScoreThreshold
+	}
+	return defaultScoreThreshold
+}
 
-	response := gocv.NewMatWithSize(resultRows, resultCols, gocv.MatTypeCV32F)
-	defer response.Close()
-	mask := gocv.NewMat()
-	defer mask.Close()
+func (t *TemplateTracker) searchMargin() float64 {
+	return t.params.searchMargin()
+}
 
-	gocv.MatchTemplate(searchMat, t.template, &response, gocv.TmCcoeffNormed, mask)
+func (p Params) searchMargin() float64 {
+	if p.SearchMargin > 0 {
+		return p.SearchMargin
+	}
+	return defaultSearchMargin
+}
 
-	_, maxVal, _, maxLoc := gocv.MinMaxLoc(response)
-	if float64(maxVal) < scoreThreshold {
-		return fallback
+func (p Params) scaleMin() float64 {
+	if p.ScaleMin > 0 {
+		return p.ScaleMin
 	}
+	return 1
+}
 
-	topLeft := image.Point{
-		X: searchRect.Min.X + maxLoc.X,
-		Y: searchRect.Min.Y + maxLoc.Y,
+func (p Params) scaleMax() float64 {
+	if p.ScaleMax > 0 {
+		return p.ScaleMax
 	}
-	center := image.Point{
-		X: topLeft.X + t.template.Cols()/2,
-		Y: topLeft.Y + t.template.Rows()/2,
+	return 1
+}
+
+// bestMatch returns the highest scoring of this frame's candidates within
+// searchRect.
+func (t *TemplateTracker) bestMatch(gray gocv.Mat, searchRect image.Rectangle) (match, bool) {
+	best, found := match{}, false
+	for _, c := range t.candidates() {
+		m, ok := t.matchCandidate(gray, searchRect, c)
+		if ok && (!found || m.score > best.score) {
+			best, found = m, true
+		}
 	}
-	t.templatePoint = center
+	return best, found
+}
+
+// lock moves the tracker onto an accepted match.
+func (t *TemplateTracker) lock(m match) {
+	t.templatePoint = m.center
+	t.scale = m.scale
+	t.angle = m.angle
+	t.lostFrames = 0
+}
 
-	return Result{X: center.X, Y: center.Y}
+// lost finishes a Lost result: with prediction on, the filter coasts and,
+// for the first few frames, its point replaces the last seen one.
+func (t *TemplateTracker) lost(res Result) Result {
+	t.lostFrames++
+	if pt, ok := t.predictor.miss(); ok && t.params.Predictive {
+		res.X, res.Y = pt.X, pt.Y
+		res.Predicted = true
+	}
+	return res
 }
 
-func (t *Tracker) Close() {
+func (t *TemplateTracker) Close() {
 	t.template.Close()
+	t.anchor.Close()
+	t.accum.Close()
 }
 
 func toGray(frame gocv.Mat) gocv.Mat {
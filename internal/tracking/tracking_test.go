@@ -0,0 +1,267 @@
+package tracking
+
+import (
+	"image"
+	"math"
+	"testing"
+
+	"open-camera-mouse/internal/camera"
+
+	"gocv.io/x/gocv"
+)
+
+// wanderPath moves the target at a few px per frame along a line, half a
+// circle and back, staying well inside a 640×480 frame.
+var wanderPath = []camera.PathSegment{
+	{Kind: camera.SegmentLine, Frames: 30, To: image.Pt(420, 260)},
+	{Kind: camera.SegmentCircle, Frames: 60, Center: image.Pt(320, 240), Turns: 0.5},
+	{Kind: camera.SegmentLine, Frames: 30, To: image.Pt(320, 240)},
+}
+
+// syntheticStep is one frame of a synthetic run: the ground truth and what
+// the tracker made of it.
+type syntheticStep struct {
+	truth camera.Truth
+	res   Result
+}
+
+// frameTransform alters a rendered frame before the tracker sees it. It
+// must leave the target's center where truth says it is.
+type frameTransform func(frame *gocv.Mat, i int, truth camera.Truth)
+
+// slowRoll rolls the target by a tenth of a degree per frame, up to 6°:
+// enough to wear down a fixed template's score, slow enough to adapt to.
+func slowRoll(frame *gocv.Mat, i int, truth camera.Truth) {
+	turnFrame(frame, image.Pt(truth.X, truth.Y), math.Min(float64(i)*0.1, 6), 1)
+}
+
+// leanIn grows the target by one scale step per frame for four frames, as
+// the user leaning toward the camera would.
+func leanIn(frame *gocv.Mat, i int, truth camera.Truth) {
+	turnFrame(frame, image.Pt(truth.X, truth.Y), 0, math.Pow(1+scaleStep, float64(min(i, 4))))
+}
+
+// tiltHead rolls the target by one rotation step per frame for three
+// frames, to 15°.
+func tiltHead(frame *gocv.Mat, i int, truth camera.Truth) {
+	turnFrame(frame, image.Pt(truth.X, truth.Y), rotationStepDeg*float64(min(i, 3)), 1)
+}
+
+func synthetic(path []camera.PathSegment) camera.SyntheticOptions {
+	return camera.SyntheticOptions{Start: image.Pt(320, 240), Path: path, Seed: 1}
+}
+
+// constellationParams fit all four patches onto the synthetic target, which
+// constellationOptions make twice the usual size.
+func constellationParams() Params {
+	params := templateParams()
+	params.Kind = KindConstellation
+	params.TemplateSizePx = 24
+	return params
+}
+
+func constellationOptions(path []camera.PathSegment) camera.SyntheticOptions {
+	opts := synthetic(path)
+	opts.TargetSize = 96
+	return opts
+}
+
+func templateParams() Params {
+	return Params{
+		Kind:           KindTemplate,
+		TemplateSizePx: 48,
+		ScoreThreshold: 0.68,
+		SearchMargin:   2,
+	}
+}
+
+// runSynthetic drives tracker along the path of opts, picking the target's
+// center on the first frame.
+func runSynthetic(t *testing.T, tracker Tracker, opts camera.SyntheticOptions, transform frameTransform) []syntheticStep {
+	t.Helper()
+	src, err := camera.NewSyntheticSource(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+
+	frame := gocv.NewMat()
+	defer frame.Close()
+	steps := make([]syntheticStep, 0, src.Frames())
+	for i := 0; i < src.Frames(); i++ {
+		truth := src.Render(i, &frame)
+		if transform != nil {
+			transform(&frame, i, truth)
+		}
+		if i == 0 {
+			if err := tracker.Pick(frame, truth.X, truth.Y); err != nil {
+				t.Fatalf("pick at (%d, %d): %v", truth.X, truth.Y, err)
+			}
+		}
+		steps = append(steps, syntheticStep{truth: truth, res: tracker.Update(frame)})
+	}
+	return steps
+}
+
+// trackingError is the largest distance between the tracked and the true
+// position over the frames of steps where the target was visible and
+// found, and how many visible frames it was lost in.
+func trackingError(steps []syntheticStep) (maxErr float64, lost int) {
+	for _, s := range steps {
+		if !s.truth.Visible {
+			continue
+		}
+		if s.res.Lost {
+			lost++
+			continue
+		}
+		x, y := s.res.Position()
+		maxErr = math.Max(maxErr, math.Hypot(x-float64(s.truth.X), y-float64(s.truth.Y)))
+	}
+	return maxErr, lost
+}
+
+// turnFrame rotates the whole frame by angle degrees counterclockwise and
+// scales it about center, as a head turning or leaning in would change the
+// patch there without moving it.
+func turnFrame(frame *gocv.Mat, center image.Point, angle, scale float64) {
+	m := gocv.GetRotationMatrix2D(center, angle, scale)
+	defer m.Close()
+	turned := gocv.NewMat()
+	defer turned.Close()
+	gocv.WarpAffine(*frame, &turned, m, image.Pt(frame.Cols(), frame.Rows()))
+	turned.CopyTo(frame)
+}
+
+func TestTrackersFollowSyntheticPath(t *testing.T) {
+	adaptive := templateParams()
+	adaptive.AdaptiveTemplate = true
+	adaptive.AdaptiveRate = 0.1
+	adaptive.AdaptiveMinScore = 0.85
+	scaled := templateParams()
+	scaled.ScaleMin, scaled.ScaleMax = 0.8, 1.25
+	rotating := templateParams()
+	rotating.RotationMaxDeg = 20
+
+	tests := []struct {
+		name      string
+		params    Params
+		opts      camera.SyntheticOptions
+		transform frameTransform
+		maxErrPx  float64
+		// check inspects the last frame's result.
+		check func(t *testing.T, last Result)
+	}{
+		{
+			name:     "template",
+			params:   templateParams(),
+			opts:     synthetic(wanderPath),
+			maxErrPx: 1,
+		},
+		{
+			name:      "adaptive",
+			params:    adaptive,
+			opts:      synthetic(wanderPath),
+			transform: slowRoll,
+			maxErrPx:  2,
+		},
+		{
+			name:      "scale",
+			params:    scaled,
+			opts:      synthetic(wanderPath),
+			transform: leanIn,
+			maxErrPx:  2,
+			check: func(t *testing.T, last Result) {
+				if want := math.Pow(1+scaleStep, 4); math.Abs(last.Scale-want) > 0.03 {
+					t.Errorf("scale %.3f, want %.3f", last.Scale, want)
+				}
+			},
+		},
+		{
+			name:      "rotation",
+			params:    rotating,
+			opts:      synthetic(wanderPath),
+			transform: tiltHead,
+			maxErrPx:  2,
+			check: func(t *testing.T, last Result) {
+				if math.Abs(last.Angle-15) > 1e-9 {
+					t.Errorf("angle %.1f°, want 15°", last.Angle)
+				}
+			},
+		},
+		{
+			name:     "flow",
+			params:   Params{Kind: KindFlow, TemplateSizePx: 48},
+			opts:     synthetic(wanderPath),
+			maxErrPx: 2,
+			check: func(t *testing.T, last Result) {
+				if last.Score <= 0 || last.Score > 1 {
+					t.Errorf("score %.2f, want the share of features kept", last.Score)
+				}
+			},
+		},
+		{
+			name:     "constellation",
+			params:   constellationParams(),
+			opts:     constellationOptions(wanderPath),
+			maxErrPx: 1,
+			check: func(t *testing.T, last Result) {
+				if len(last.Patches) != len(constellationOffsets) {
+					t.Fatalf("%d patches, want %d", len(last.Patches), len(constellationOffsets))
+				}
+				for i, p := range last.Patches {
+					if p.State != PatchOK {
+						t.Errorf("patch %d is %s, want %s", i, p.State, PatchOK)
+					}
+				}
+			},
+		},
+		{
+			name:      "marker",
+			params:    Params{Kind: KindMarker, TemplateSizePx: 48, SearchMargin: 2},
+			opts:      synthetic(wanderPath),
+			transform: sticker,
+			maxErrPx:  1,
+			check: func(t *testing.T, last Result) {
+				if last.Precise == nil {
+					t.Error("no sub-pixel position")
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := New(tt.params)
+			defer tracker.Close()
+			steps := runSynthetic(t, tracker, tt.opts, tt.transform)
+			maxErr, lost := trackingError(steps)
+			if lost > 0 {
+				t.Errorf("lost the visible target in %d of %d frames", lost, len(steps))
+			}
+			if maxErr > tt.maxErrPx {
+				t.Errorf("max error %.1f px, want at most %.1f", maxErr, tt.maxErrPx)
+			}
+			if tt.check != nil {
+				tt.check(t, steps[len(steps)-1].res)
+			}
+		})
+	}
+}
+
+func TestAdaptiveTemplateStaysAnchored(t *testing.T) {
+	params := templateParams()
+	params.AdaptiveTemplate = true
+	params.AdaptiveRate = 0.1
+	params.AdaptiveMinScore = 0.85
+	tracker := NewTemplate(params)
+	defer tracker.Close()
+
+	runSynthetic(t, tracker, synthetic(wanderPath), slowRoll)
+	sim := similarity(tracker.template, tracker.anchor)
+	if sim > 0.999 {
+		t.Errorf("template still matches the picked patch exactly (%.3f); nothing was blended in", sim)
+	}
+	if sim < anchorMinScore {
+		t.Errorf("template drifted from the picked patch: similarity %.3f, want at least %.1f", sim, anchorMinScore)
+	}
+}
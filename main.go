@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
 
 	"gocv.io/x/gocv"
 
@@ -19,13 +20,43 @@ var assets embed.FS
 var version = "dev"
 
 func main() {
+	var flags sourceFlags
+	var replayDir, trajectoryPath, goldenPath string
 	for _, arg := range os.Args[1:] {
 		if arg == "--smoke-test" {
 			runSmokeTest()
 		}
+		if arg == "--synthetic-check" {
+			runSyntheticCheck("")
+		}
+		if path, ok := strings.CutPrefix(arg, "--synthetic-check="); ok {
+			runSyntheticCheck(path)
+		}
+		if path, ok := strings.CutPrefix(arg, "--video="); ok {
+			flags.videoPath = path
+		}
+		if arg == "--synthetic" {
+			flags.synthetic = true
+		}
+		if path, ok := strings.CutPrefix(arg, "--synthetic="); ok {
+			flags.synthetic = true
+			flags.syntheticScript = path
+		}
+		if dir, ok := strings.CutPrefix(arg, "--replay="); ok {
+			replayDir = dir
+		}
+		if path, ok := strings.CutPrefix(arg, "--trajectory="); ok {
+			trajectoryPath = path
+		}
+		if path, ok := strings.CutPrefix(arg, "--golden="); ok {
+			goldenPath = path
+		}
+	}
+	if replayDir != "" {
+		runReplay(replayDir, trajectoryPath, goldenPath)
 	}
 
-	app, err := NewApp()
+	app, err := NewApp(flags)
 	if err != nil {
 		log.Fatal(err)
 	}
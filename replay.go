@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	appsvc "open-camera-mouse/internal/app"
+	"open-camera-mouse/internal/mouse"
+)
+
+// Replay runs against a fixed virtual screen so trajectories don't depend on
+// the machine they are produced on.
+const (
+	replayScreenWidth  = 1920
+	replayScreenHeight = 1080
+)
+
+// runReplay replays a recorded session headlessly and writes the resulting
+// trajectory to trajectoryPath ("" for stdout). With goldenPath set, the
+// trajectory is also compared against that file and the process exits
+// non-zero at the first difference.
+func runReplay(sessionDir, trajectoryPath, goldenPath string) {
+	var trajectory bytes.Buffer
+	cursor := mouse.NewVirtualCursor(replayScreenWidth, replayScreenHeight)
+	fmt.Fprintln(&trajectory, "frame\tt_ms\tlost\tx\ty\tscore\tmoved\tcursor_x\tcursor_y\tclick")
+	err := appsvc.Replay(sessionDir, cursor, func(f appsvc.ReplayFrame) {
+		writeTrajectoryLine(&trajectory, f, cursor)
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "replay failed: %v\n", err)
+		os.Exit(1)
+	}
+
+	if trajectoryPath == "" {
+		_, _ = os.Stdout.Write(trajectory.Bytes())
+	} else if err := os.WriteFile(trajectoryPath, trajectory.Bytes(), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "write trajectory: %v\n", err)
+		os.Exit(1)
+	}
+
+	if goldenPath != "" {
+		golden, err := os.ReadFile(goldenPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "read golden: %v\n", err)
+			os.Exit(1)
+		}
+		if line, want, got, ok := firstDiff(golden, trajectory.Bytes()); !ok {
+			fmt.Fprintf(os.Stderr, "trajectory differs from %s at line %d:\n  want: %s\n  got:  %s\n",
+				goldenPath, line, want, got)
+			os.Exit(1)
+		}
+		fmt.Fprintln(os.Stderr, "trajectory matches golden")
+	}
+	os.Exit(0)
+}
+
+// writeTrajectoryLine writes one tab-separated line per frame. Scores are
+// rounded so harmless floating-point noise doesn't show up as a regression;
+// "-" marks a paused tracker or cursor.
+func writeTrajectoryLine(w io.Writer, f appsvc.ReplayFrame, cursor *mouse.VirtualCursor) {
+	lost, x, y, score := "-", "-", "-", "-"
+	if r := f.Tracking; r != nil {
+		lost = fmt.Sprint(r.Lost)
+		x, y = fmt.Sprint(r.X), fmt.Sprint(r.Y)
+		score = fmt.Sprintf("%.3f", r.Score)
+	}
+	moved, click := "-", "-"
+	if c := f.Cursor; c != nil {
+		moved = fmt.Sprint(c.Moved)
+		if c.Click != "" {
+			click = c.Click
+		}
+	}
+	fmt.Fprintf(w, "%d\t%d\t%s\t%s\t%s\t%s\t%s\t%d\t%d\t%s\n",
+		f.Index, f.T.Milliseconds(), lost, x, y, score, moved, cursor.X, cursor.Y, click)
+}
+
+// firstDiff compares two trajectories line by line. ok is false when they
+// differ, with the 1-based line number and both versions of that line.
+func firstDiff(want, got []byte) (line int, wantLine, gotLine string, ok bool) {
+	ws := bufio.NewScanner(bytes.NewReader(want))
+	gs := bufio.NewScanner(bytes.NewReader(got))
+	for {
+		line++
+		wOK, gOK := ws.Scan(), gs.Scan()
+		if !wOK && !gOK {
+			return 0, "", "", true
+		}
+		if wOK != gOK || ws.Text() != gs.Text() {
+			return line, ws.Text(), gs.Text(), false
+		}
+	}
+}
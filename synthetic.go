@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+
+	"open-camera-mouse/internal/camera"
+	"open-camera-mouse/internal/config"
+	"open-camera-mouse/internal/mouse"
+	"open-camera-mouse/internal/tracking"
+)
+
+// syntheticTolerancePx is how far a tracked position may be from the ground
+// truth before it counts as a wrong lock; jitter and rounding stay well
+// inside it.
+const syntheticTolerancePx = 2.0
+
+// syntheticMouse makes the cursor repeat each tracked step one to one: unit
+// gain, no smoothing, and the frame's own direction.
+var syntheticMouse = mouse.Params{GainMultiplier: 1, Smoothing: 1}
+
+// loadSynthetic builds a synthetic source from a JSON script, or the demo
+// path when scriptPath is empty.
+func loadSynthetic(scriptPath string, loop bool) (*camera.SyntheticSource, error) {
+	var opts camera.SyntheticOptions
+	if scriptPath != "" {
+		var err error
+		if opts, err = camera.LoadSyntheticOptions(scriptPath); err != nil {
+			return nil, err
+		}
+	}
+	opts.Loop = loop
+	return camera.NewSyntheticSource(opts)
+}
+
+// runSyntheticCheck streams one pass of a synthetic path through
+// tracking.Tracker and a mouse.Mouse driving a VirtualCursor, picking the
+// target's true center on the first frame, and prints one line per frame
+// comparing the tracked position and the cursor's step with the ground
+// truth. It exits non-zero on a wrong lock (tracked more than
+// syntheticTolerancePx away), a false lock (tracked while occluded) or a
+// wrong cursor move (a step unlike the target's).
+//
+// The stream runs in real time, so frames the check falls behind on are
+// dropped like a camera's; the ground truth is looked up by Frame.Seq.
+func runSyntheticCheck(scriptPath string) {
+	os.Exit(syntheticCheck(scriptPath))
+}
+
+func syntheticCheck(scriptPath string) int {
+	src, err := loadSynthetic(scriptPath, false)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "synthetic source: %v\n", err)
+		return 1
+	}
+	defer src.Close()
+
+	tracker := tracking.New(tracking.Params{TemplateSizePx: config.DefaultTemplateSizePx})
+	defer tracker.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	frames, err := src.Stream(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "synthetic source: %v\n", err)
+		return 1
+	}
+
+	var cursor *mouse.VirtualCursor
+	var m *mouse.Mouse
+	var prev camera.Truth
+	var prevX, prevY int
+	var n, lost, wrong, falseLocks, wrongMoves int
+	var maxErr float64
+	fmt.Println("frame\ttrue_x\ttrue_y\tvisible\tlost\tx\ty\tscore\terror\tcursor_x\tcursor_y")
+	for f := range frames {
+		truth := src.Truth(f.Seq)
+		if m == nil {
+			if err := tracker.Pick(f.Mat, truth.X, truth.Y); err != nil {
+				f.Release()
+				fmt.Fprintf(os.Stderr, "pick failed: %v\n", err)
+				return 1
+			}
+			// A screen three frames across keeps the cursor off its edges
+			// wherever the path goes.
+			cursor = mouse.NewVirtualCursor(3*f.Width, 3*f.Height)
+			m = mouse.NewWithBackend(syntheticMouse, cursor)
+		}
+		res := tracker.Update(f.Mat)
+		f.Release()
+		n++
+
+		out := m.Update(float64(res.X), float64(res.Y), res.Lost, f.CapturedAt)
+		if out.Moved && truth.Visible && prev.Visible {
+			dx := float64(cursor.X - prevX - (truth.X - prev.X))
+			dy := float64(cursor.Y - prevY - (truth.Y - prev.Y))
+			if math.Hypot(dx, dy) > 2*syntheticTolerancePx {
+				wrongMoves++
+			}
+		}
+		prev, prevX, prevY = truth, cursor.X, cursor.Y
+
+		dist := math.Hypot(float64(res.X-truth.X), float64(res.Y-truth.Y))
+		switch {
+		case !truth.Visible && !res.Lost:
+			falseLocks++
+		case truth.Visible && res.Lost:
+			lost++
+		case truth.Visible:
+			maxErr = math.Max(maxErr, dist)
+			if dist > syntheticTolerancePx {
+				wrong++
+			}
+		}
+		fmt.Printf("%d\t%d\t%d\t%t\t%t\t%d\t%d\t%.3f\t%.1f\t%d\t%d\n",
+			f.Seq-1, truth.X, truth.Y, truth.Visible, res.Lost, res.X, res.Y, res.Score, dist, cursor.X, cursor.Y)
+	}
+
+	fmt.Fprintf(os.Stderr,
+		"%d of %d frames: max error %.1f px, %d lost while visible, %d wrong locks, %d false locks, %d wrong cursor moves\n",
+		n, src.Frames(), maxErr, lost, wrong, falseLocks, wrongMoves)
+	if wrong > 0 || falseLocks > 0 || wrongMoves > 0 {
+		return 1
+	}
+	return 0
+}